@@ -0,0 +1,134 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/tweekes0/pal-bot/internal/models"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Subcommands of the 'alias' command and the number of args they need including themselves
+var aliasSubcommands = map[string]int{"add": 3, "remove": 2, "list": 1}
+
+// Gets the name of the soundbite an alias points to, a name that is not an alias is returned as is
+func (ctx *Context) resolveAlias(name string) (string, error) {
+	a, err := ctx.aliasModel.Get(name)
+	if errors.Is(err, models.ErrDoesNotExist) {
+		return name, nil
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return a.SoundName, nil
+}
+
+// Bot will add an alias that plays a soundbite, aliases can't be named after commands
+func (ctx *Context) addAlias(s *discordgo.Session, m *discordgo.MessageCreate, name, sound string) error {
+	if _, ok := ctx.commands[fmt.Sprint(ctx.botCfg.CommandPrefix, strings.ToLower(name))]; ok {
+		msg := fmt.Sprintf("Sorry <@%v>, **%v** can't be used as an alias", m.Author.ID, name)
+		_, _ = s.ChannelMessageSend(m.ChannelID, msg)
+		return ErrInvalidAlias
+	}
+
+	err := ctx.aliasModel.Insert(name, sound, m.Author.Username, m.Author.ID)
+	if errors.Is(err, models.ErrUniqueConstraint) {
+		msg := fmt.Sprintf("Sorry <@%v>, **%v** is already a soundbite or alias", m.Author.ID, name)
+		_, _ = s.ChannelMessageSend(m.ChannelID, msg)
+		return err
+	}
+
+	if err != nil {
+		return err
+	}
+
+	msg := fmt.Sprintf("**%v%v** now plays **%v**", ctx.botCfg.CommandPrefix, name, sound)
+	_, _ = s.ChannelMessageSend(m.ChannelID, msg)
+	return nil
+}
+
+// Bot will remove an alias the author added, the soundbite is kept
+func (ctx *Context) removeAlias(s *discordgo.Session, m *discordgo.MessageCreate, name string) error {
+	a, err := ctx.aliasModel.Get(name)
+	if err != nil {
+		return err
+	}
+
+	if err := ctx.authorizeOwner(s, m, a.Name, a.UserID, a.Username); err != nil {
+		return err
+	}
+
+	if err := ctx.aliasModel.Delete(a.Name); err != nil {
+		return err
+	}
+
+	_, _ = s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("%v has been removed", a.Name))
+	return nil
+}
+
+// Bot will list the aliases of a soundbite, or every alias if no soundbite is given
+func (ctx *Context) listAliases(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
+	sound := ""
+	if len(args) > 0 {
+		name, err := ctx.resolveAlias(args[0])
+		if err != nil {
+			return err
+		}
+
+		sound = name
+	}
+
+	aliases, err := ctx.aliasModel.List(sound)
+	if err != nil {
+		return err
+	}
+
+	if len(aliases) == 0 {
+		_, _ = s.ChannelMessageSend(m.ChannelID, "there are no aliases :(")
+		return nil
+	}
+
+	var b strings.Builder
+	fmt.Fprint(&b, "**Aliases:** \n")
+	for _, a := range aliases {
+		fmt.Fprintf(&b, "%v%v plays %v\n", ctx.botCfg.CommandPrefix, a.Name, a.SoundName)
+	}
+
+	_, _ = s.ChannelMessageSend(m.ChannelID, b.String())
+	return nil
+}
+
+// Wrapper function for the 'alias' command
+func (ctx *Context) aliasCommand() func(*discordgo.Session, *discordgo.MessageCreate, []string) error {
+	return func(s *discordgo.Session, m *discordgo.MessageCreate, st []string) error {
+		if len(st) < 1 {
+			ctx.help(s, m, "alias")
+			return ErrNotEnoughArgs
+		}
+
+		sub := strings.ToLower(st[0])
+		n, ok := aliasSubcommands[sub]
+		if !ok {
+			ctx.help(s, m, "alias")
+			return ErrInvalidSubcommand
+		}
+
+		if len(st) < n {
+			ctx.help(s, m, "alias")
+			return ErrNotEnoughArgs
+		}
+
+		switch sub {
+		case "add":
+			return ctx.addAlias(s, m, st[1], st[2])
+		case "remove":
+			return ctx.removeAlias(s, m, st[1])
+		}
+
+		return ctx.listAliases(s, m, st[1:])
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tweekes0/pal-bot/internal/models"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	MAX_AUDIT_ENTRIES = 15 // The maximum number of entries listed in an 'audit' reply
+)
+
+// Creates an audit log entry for an action the author of a message took on a soundbite
+func auditEntry(m *discordgo.MessageCreate, action models.AuditAction, sound *models.Soundbite) *models.AuditEntry {
+	return &models.AuditEntry{
+		Action:   action,
+		SoundID:  sound.ID,
+		Sound:    sound.Name,
+		UserID:   m.Author.ID,
+		Username: m.Author.Username,
+		GuildID:  m.GuildID,
+	}
+}
+
+// Formats what an audit log entry did and who did it
+func formatAuditEntry(e *models.AuditEntry) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "**%v** %v", e.Action, e.Sound)
+
+	switch {
+	case e.OldValue != "" && e.NewValue != "":
+		fmt.Fprintf(&b, " from %v to %v", e.OldValue, e.NewValue)
+	case e.NewValue != "":
+		fmt.Fprintf(&b, " %v", e.NewValue)
+	}
+
+	if e.UserID == "" {
+		fmt.Fprint(&b, " by the bot")
+	} else {
+		fmt.Fprintf(&b, " by %v", e.Username)
+	}
+
+	return b.String()
+}
+
+// Bot will add an entry to the audit log and post it to the log channel if there is one. The
+// change has already been made so an entry that can't be added is only logged.
+func (ctx *Context) record(s *discordgo.Session, e *models.AuditEntry) {
+	if _, err := ctx.auditModel.Insert(e); err != nil {
+		ctx.errorLogger.Println(err)
+		return
+	}
+
+	if s == nil || ctx.botCfg.Audit.ChannelID == "" {
+		return
+	}
+
+	_, _ = s.ChannelMessageSend(ctx.botCfg.Audit.ChannelID, formatAuditEntry(e))
+}
+
+// Bot will list the newest audit log entries, of a soundbite or of the actions a user took
+func (ctx *Context) listAudit(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
+	opts := models.AuditOptions{Limit: MAX_AUDIT_ENTRIES}
+	switch {
+	case len(m.Mentions) > 0:
+		opts.UserID = m.Mentions[0].ID
+	case len(args) > 0:
+		opts.Sound = args[0]
+	}
+
+	entries, err := ctx.auditModel.List(opts)
+	if err != nil {
+		return err
+	}
+
+	if len(entries) == 0 {
+		_, _ = s.ChannelMessageSend(m.ChannelID, "there is nothing in the audit log :(")
+		return nil
+	}
+
+	var b strings.Builder
+	fmt.Fprint(&b, "**Audit log:** \n")
+	for _, e := range entries {
+		fmt.Fprintf(&b, "<t:%v:f> %v\n", e.Created.Unix(), formatAuditEntry(e))
+	}
+
+	_, _ = s.ChannelMessageSend(m.ChannelID, b.String())
+	return nil
+}
+
+// Wrapper function for the 'audit' command
+func (ctx *Context) auditCommand() func(*discordgo.Session, *discordgo.MessageCreate, []string) error {
+	return func(s *discordgo.Session, m *discordgo.MessageCreate, st []string) error {
+		return ctx.listAudit(s, m, st)
+	}
+}
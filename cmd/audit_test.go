@@ -0,0 +1,111 @@
+package main
+
+import (
+	"io/ioutil"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/tweekes0/pal-bot/config"
+	"github.com/tweekes0/pal-bot/internal/library"
+	"github.com/tweekes0/pal-bot/internal/models"
+	test "github.com/tweekes0/pal-bot/internal/testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestFormatAuditEntry(t *testing.T) {
+	tt := []struct {
+		description string
+		input       *models.AuditEntry
+		expected    string
+	}{
+		{
+			"action without values",
+			&models.AuditEntry{Action: models.AuditDelete, Sound: "bruh", UserID: "1", Username: "pal"},
+			"**delete** bruh by pal",
+		},
+		{
+			"action with old and new values",
+			&models.AuditEntry{Action: models.AuditRename, Sound: "bruh", UserID: "1", Username: "pal",
+				OldValue: "bruh", NewValue: "b"},
+			"**rename** bruh from bruh to b by pal",
+		},
+		{
+			"action with new values",
+			&models.AuditEntry{Action: models.AuditTag, Sound: "bruh", UserID: "1", Username: "pal",
+				NewValue: "memes, classics"},
+			"**tag** bruh memes, classics by pal",
+		},
+		{
+			"action taken by the bot",
+			&models.AuditEntry{Action: models.AuditPurge, Sound: "bruh"},
+			"**purge** bruh by the bot",
+		},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			test.AssertType(t, formatAuditEntry(tc.input), tc.expected)
+		})
+	}
+}
+
+func TestRecordRepairs(t *testing.T) {
+	f, err := ioutil.TempFile("", "*")
+	if err != nil {
+		t.Fatalf("failed to create file: %v", err)
+	}
+	defer os.Remove(f.Name())
+
+	db, err := openDB(f.Name())
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	ctx := &Context{
+		soundbiteModel: &models.SoundbiteModel{DB: db},
+		auditModel:     &models.AuditModel{DB: db},
+		botCfg:         &config.BotConfig{},
+		errorLogger:    log.New(ioutil.Discard, "", 0),
+	}
+	if err := ctx.soundbiteModel.Initialize(); err != nil {
+		t.Fatalf("failed to initialize soundbites: %v", err)
+	}
+	if err := ctx.auditModel.Initialize(); err != nil {
+		t.Fatalf("failed to initialize audit log: %v", err)
+	}
+
+	r := &library.Report{Issues: []*library.Issue{
+		{Kind: library.MissingFile, SoundID: 1, Name: "sound0", Repaired: true},
+		{Kind: library.HashMismatch, SoundID: 2, Name: "sound1"},
+		{Kind: library.OrphanFile, FilePath: "/path/to/orphan.dca", Repaired: true},
+	}}
+
+	m := &discordgo.MessageCreate{Message: &discordgo.Message{
+		GuildID: "guild",
+		Author:  &discordgo.User{ID: "111111", Username: "pal"},
+	}}
+
+	ctx.recordRepairs(nil, m, r)
+	ctx.recordRepairs(nil, nil, r)
+
+	entries, err := ctx.auditModel.List(models.AuditOptions{Limit: 10})
+	test.AssertError(t, err, nil)
+
+	expected := []string{
+		"**repair** sound0 missing file by the bot",
+		"**repair** sound0 missing file by pal",
+	}
+
+	got := []string{}
+	for _, e := range entries {
+		got = append(got, formatAuditEntry(e))
+	}
+
+	test.AssertType(t, got, expected)
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	MAX_COMBO_SOUNDS = 10 // The maximum number of soundbites in a combo
+)
+
+// Subcommands of the 'combo' command and the number of args they need including
+// themselves. Subcommand names cannot be used as combo names.
+var comboSubcommands = map[string]int{"create": 3, "update": 3, "delete": 2, "show": 2, "list": 1}
+
+// Checks the soundbites of a combo and lets the author know if there are too many
+func checkComboSounds(s *discordgo.Session, m *discordgo.MessageCreate, sounds []string) error {
+	if len(sounds) <= MAX_COMBO_SOUNDS {
+		return nil
+	}
+
+	msg := fmt.Sprintf("Sorry <@%v>, combos can only have %v soundbites", m.Author.ID, MAX_COMBO_SOUNDS)
+	_, _ = s.ChannelMessageSend(m.ChannelID, msg)
+
+	return ErrTooManySounds
+}
+
+// Bot will create a combo that plays soundbites in the given order
+func (ctx *Context) createCombo(s *discordgo.Session, m *discordgo.MessageCreate, name string, sounds []string) error {
+	if _, ok := comboSubcommands[strings.ToLower(name)]; ok {
+		msg := fmt.Sprintf("Sorry <@%v>, **%v** can't be used as a combo name", m.Author.ID, name)
+		_, _ = s.ChannelMessageSend(m.ChannelID, msg)
+		return ErrInvalidSubcommand
+	}
+
+	if err := checkComboSounds(s, m, sounds); err != nil {
+		return err
+	}
+
+	_, err := ctx.playlistModel.Insert(name, m.Author.Username, m.Author.ID, sounds)
+	if err != nil {
+		return err
+	}
+
+	msg := fmt.Sprintf("Your combo is ready. Play it with **%vcombo %v**", ctx.botCfg.CommandPrefix, name)
+	_, _ = s.ChannelMessageSend(m.ChannelID, msg)
+	return nil
+}
+
+// Bot will replace the soundbites of a combo the author created
+func (ctx *Context) updateCombo(s *discordgo.Session, m *discordgo.MessageCreate, name string, sounds []string) error {
+	p, err := ctx.playlistModel.Get(name)
+	if err != nil {
+		return err
+	}
+
+	if err := ctx.authorizeOwner(s, m, p.Name, p.UserID, p.Username); err != nil {
+		return err
+	}
+
+	if err := checkComboSounds(s, m, sounds); err != nil {
+		return err
+	}
+
+	if err := ctx.playlistModel.Update(p.Name, sounds); err != nil {
+		return err
+	}
+
+	_, _ = s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("%v has been updated", p.Name))
+	return nil
+}
+
+// Bot will delete a combo the author created, the soundbites in it are kept
+func (ctx *Context) deleteCombo(s *discordgo.Session, m *discordgo.MessageCreate, name string) error {
+	p, err := ctx.playlistModel.Get(name)
+	if err != nil {
+		return err
+	}
+
+	if err := ctx.authorizeOwner(s, m, p.Name, p.UserID, p.Username); err != nil {
+		return err
+	}
+
+	if err := ctx.playlistModel.Delete(p.Name); err != nil {
+		return err
+	}
+
+	_, _ = s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("%v has been deleted", p.Name))
+	return nil
+}
+
+// Bot will list every combo
+func (ctx *Context) listCombos(s *discordgo.Session, m *discordgo.MessageCreate) error {
+	names, err := ctx.playlistModel.Names()
+	if err != nil {
+		return err
+	}
+
+	if len(names) == 0 {
+		_, _ = s.ChannelMessageSend(m.ChannelID, "there are no combos :(")
+		return nil
+	}
+
+	var b strings.Builder
+	fmt.Fprint(&b, "**Combos:** \n")
+	for _, name := range names {
+		fmt.Fprintf(&b, "%vcombo %v\n", ctx.botCfg.CommandPrefix, name)
+	}
+
+	_, _ = s.ChannelMessageSend(m.ChannelID, b.String())
+	return nil
+}
+
+// Bot will show the soundbites of a combo in play order
+func (ctx *Context) showCombo(s *discordgo.Session, m *discordgo.MessageCreate, name string) error {
+	p, err := ctx.playlistModel.Get(name)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, len(p.Sounds))
+	for i, sound := range p.Sounds {
+		names[i] = sound.Name
+	}
+
+	msg := fmt.Sprintf("**%v** by %v plays %v", p.Name, p.Username, strings.Join(names, ", "))
+	if len(names) == 0 {
+		msg = fmt.Sprintf("**%v** by %v has no soundbites left", p.Name, p.Username)
+	}
+
+	_, _ = s.ChannelMessageSend(m.ChannelID, msg)
+	return nil
+}
+
+// Bot will play the soundbites of a combo back-to-back in the author's voice channel
+func (ctx *Context) playCombo(s *discordgo.Session, m *discordgo.MessageCreate, name string) error {
+	p, err := ctx.playlistModel.Get(name)
+	if err != nil {
+		return err
+	}
+
+	if len(p.Sounds) == 0 {
+		return nil
+	}
+
+	return ctx.streamSoundBite(s, m, p.Sounds...)
+}
+
+// Wrapper function for the 'combo' command
+func (ctx *Context) comboCommand() func(*discordgo.Session, *discordgo.MessageCreate, []string) error {
+	return func(s *discordgo.Session, m *discordgo.MessageCreate, st []string) error {
+		if len(st) < 1 {
+			ctx.help(s, m, "combo")
+			return ErrNotEnoughArgs
+		}
+
+		sub := strings.ToLower(st[0])
+		if n, ok := comboSubcommands[sub]; ok && len(st) < n {
+			ctx.help(s, m, "combo")
+			return ErrNotEnoughArgs
+		}
+
+		switch sub {
+		case "create":
+			return ctx.createCombo(s, m, st[1], st[2:])
+		case "update":
+			return ctx.updateCombo(s, m, st[1], st[2:])
+		case "delete":
+			return ctx.deleteCombo(s, m, st[1])
+		case "show":
+			return ctx.showCombo(s, m, st[1])
+		case "list":
+			return ctx.listCombos(s, m)
+		}
+
+		return ctx.playCombo(s, m, st[0])
+	}
+}
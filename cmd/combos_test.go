@@ -0,0 +1,22 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestComboRateLimit(t *testing.T) {
+	ctx, teardown := handlerTestSetup(t)
+	defer teardown()
+
+	s, d := fakeSession(t)
+
+	// the first use takes the user's only play
+	ctx.messageCreate(s, fakeMessage("111111", "!combo list"))
+	ctx.messageCreate(s, fakeMessage("111111", "!combo bruh"))
+
+	sent := d.sent()
+	if len(sent) != 2 || !strings.HasPrefix(sent[1], "Slow down <@111111>") {
+		t.Fatalf("expected the combo to be rate limited, got %q", sent)
+	}
+}
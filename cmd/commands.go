@@ -1,12 +1,19 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"io"
+	"os"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/tweekes0/pal-bot/config"
+	"github.com/tweekes0/pal-bot/internal/library"
+	"github.com/tweekes0/pal-bot/internal/models"
+	"github.com/tweekes0/pal-bot/internal/player"
+	"github.com/tweekes0/pal-bot/internal/ratelimit"
 	"github.com/tweekes0/pal-bot/internal/sounds"
 
 	"github.com/bwmarrin/discordgo"
@@ -16,24 +23,59 @@ type Command struct {
 	Description string
 	Help        string
 	Action      func(*discordgo.Session, *discordgo.MessageCreate, []string) error
+	Permission  Permission         // The permission needed to use the command
+	Limiter     *ratelimit.Limiter // Limits how often a user can use the command, nil if unlimited
 }
 
 type Commands map[string]Command
 
-// Bot will join the voice channel that is specified in config file
+// Bot will join the voice channel that the author of the message is in
 func (ctx *Context) joinVoice(s *discordgo.Session, m *discordgo.MessageCreate) error {
 	id := getChannelID(s, m)
 	if id == "" {
 		return ErrUserNotInVC
 	}
 
-	var err error
-	ctx.vc, err = s.ChannelVoiceJoin(m.GuildID, id, false, true)
-	if err != nil {
-		return err
+	_, err := ctx.joinChannel(s, m.GuildID, id)
+	return err
+}
+
+// Bot will join a voice channel in a guild, a new voice connection gets a new player
+func (ctx *Context) joinChannel(s *discordgo.Session, guildID, channelID string) (*playback, error) {
+	join := func() (*discordgo.VoiceConnection, error) {
+		return s.ChannelVoiceJoin(guildID, channelID, false, true)
 	}
 
-	return nil
+	return ctx.voice.join(join, func(vc *discordgo.VoiceConnection) *playback {
+		return ctx.startPlayback(s, vc)
+	})
+}
+
+// Creates the player of a new voice connection along with its supervisor and idle timer
+func (ctx *Context) startPlayback(s *discordgo.Session, vc *discordgo.VoiceConnection) *playback {
+	p := player.New(voiceOutput(vc))
+
+	rejoin := func() (player.Output, error) { return ctx.rejoinVoice(s, vc) }
+	lost := func(err error) { ctx.voiceLost(vc, err) }
+	timeout := time.Duration(ctx.botCfg.Voice.IdleTimeout) * time.Minute
+
+	return &playback{
+		player:     p,
+		supervisor: player.NewSupervisor(ctx.clock, p, voiceSupervision, rejoin, lost),
+		idle:       player.NewIdleTimer(ctx.clock, timeout, func() { ctx.leaveIdle(vc) }),
+	}
+}
+
+// Bot will leave a voice connection that has not played anything for the idle timeout
+func (ctx *Context) leaveIdle(vc *discordgo.VoiceConnection) {
+	if ctx.voice.leave(vc) == nil {
+		return
+	}
+
+	ctx.infoLogger.Printf("leaving voice channel %v after %v idle minutes", voiceChannelID(vc), ctx.botCfg.Voice.IdleTimeout)
+	if err := vc.Disconnect(); err != nil {
+		ctx.errorLogger.Println(err)
+	}
 }
 
 // Wrapper function for the 'join' command
@@ -49,11 +91,12 @@ func (ctx *Context) joinCommand() func(*discordgo.Session, *discordgo.MessageCre
 
 // Bot will leave the voice channel it is currently in
 func (ctx *Context) leaveVoice(s *discordgo.Session, m *discordgo.MessageCreate) error {
-	if !ctx.joinedVoice {
+	vc := ctx.voice.leave(nil)
+	if vc == nil {
 		return ErrBotNotInVC
 	}
 
-	if err := ctx.vc.Disconnect(); err != nil {
+	if err := vc.Disconnect(); err != nil {
 		return err
 	}
 
@@ -71,21 +114,12 @@ func (ctx *Context) leaveCommand() func(*discordgo.Session, *discordgo.MessageCr
 	}
 }
 
-// Bot will load an audio file from disc and play it in the voice channel specified in config file
+// Bot will load an audio file from disc and play it in the author's voice channel
 func (ctx *Context) playSound(s *discordgo.Session, m *discordgo.MessageCreate, name string) error {
-	if sound, ok := ctx.soundbiteCache[name]; ok {
-		if err := ctx.streamSoundBite(s, m, sound); err != nil {
-			return err
-		}
-
-		return nil
-	}
-
-	soundbite, err := ctx.soundbiteModel.Get(name)
+	soundbite, err := ctx.getSoundbite(name)
 	if err != nil {
 		return err
 	}
-	ctx.soundbiteCache[name] = soundbite
 
 	if err := ctx.streamSoundBite(s, m, soundbite); err != nil {
 		return err
@@ -94,11 +128,30 @@ func (ctx *Context) playSound(s *discordgo.Session, m *discordgo.MessageCreate,
 	return nil
 }
 
+// Gets a soundbite from the cache, or from the db if it has not been played yet
+func (ctx *Context) getSoundbite(name string) (*models.Soundbite, error) {
+	if sound, ok := ctx.soundbiteCache.get(name); ok {
+		return sound, nil
+	}
+
+	soundbite, err := ctx.soundbiteModel.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	ctx.soundbiteCache.set(name, soundbite)
+
+	return soundbite, nil
+}
+
 // Bot will create audio file from youtube video
 func (ctx *Context) clip(s *discordgo.Session, m *discordgo.MessageCreate, name, url, startTime string, duration int) error {
 	start, dur := getRuntime(startTime, duration)
 
-	f, mp3, err := sounds.CreateDCAFile(config.AUDIO_DIR, url, start, dur)
+	if err := ctx.checkQuota(s, m, 1, time.Duration(dur)*time.Second); err != nil {
+		return err
+	}
+
+	f, mp3, video, err := sounds.CreateDCAFile(config.AUDIO_DIR, url, start, dur)
 	if err != nil {
 		return err
 	}
@@ -108,11 +161,44 @@ func (ctx *Context) clip(s *discordgo.Session, m *discordgo.MessageCreate, name,
 		return err
 	}
 
-	_, err = ctx.soundbiteModel.Insert(name, m.Author.Username, m.Author.ID, f.Name(), hash)
+	length, err := sounds.DCADuration(f.Name())
+	if err != nil {
+		return err
+	}
+
+	// check again with the real length, other soundbites may have been added while the clip was made
+	if err := ctx.checkQuota(s, m, 1, length); err != nil {
+		sounds.DeleteFile(f.Name())
+		sounds.DeleteFile(mp3.Name())
+		return err
+	}
+
+	offset, err := sounds.StringToDuration(start)
+	if err != nil {
+		return err
+	}
+
+	soundbite := &models.Soundbite{
+		Name:         name,
+		Username:     m.Author.Username,
+		UserID:       m.Author.ID,
+		FilePath:     f.Name(),
+		FileHash:     hash,
+		Duration:     length,
+		SourceType:   models.SourceYoutube,
+		SourceURL:    url,
+		Start:        offset,
+		SourceTitle:  video.Title,
+		SourceAuthor: video.Author,
+	}
+
+	soundbite.ID, err = ctx.soundbiteModel.Insert(soundbite)
 	if err != nil {
 		return err
 	}
 
+	ctx.record(s, auditEntry(m, models.AuditCreate, soundbite))
+
 	ms := &discordgo.MessageSend{
 		Content: fmt.Sprintf("Your clip is ready. Play it with **!%v**", name),
 		Files:   []*discordgo.File{createDiscordFile(name, mp3)},
@@ -137,7 +223,10 @@ func (ctx *Context) clipCommand() func(*discordgo.Session, *discordgo.MessageCre
 		}
 
 		if err := ctx.clip(s, m, args.Name, args.Url, args.Start, args.Duration); err != nil {
-			ctx.help(s, m, "clip")
+			if !errors.Is(err, ErrQuotaExceeded) {
+				ctx.help(s, m, "clip")
+			}
+
 			return err
 		}
 
@@ -152,20 +241,59 @@ func (ctx *Context) deleteSound(s *discordgo.Session, m *discordgo.MessageCreate
 		return err
 	}
 
+	if err := ctx.authorizeSoundbite(s, m, sound); err != nil {
+		return err
+	}
+
+	return ctx.removeSound(s, m, sound)
+}
+
+// Moves a soundbite to the trash without checking who created it, the soundbite
+// and its file are deleted straight away when the trash is disabled
+func (ctx *Context) removeSound(s *discordgo.Session, m *discordgo.MessageCreate, sound *models.Soundbite) error {
 	// remove item from cache if it is there.
-	delete(ctx.soundbiteCache, name)
+	ctx.soundbiteCache.remove(sound.Name)
 
-	err = ctx.soundbiteModel.Delete(name, m.Author.ID)
-	if err != nil {
+	if ctx.botCfg.Trash.Retention <= 0 {
+		if err := ctx.purgeSound(sound); err != nil {
+			return err
+		}
+
+		ctx.record(s, auditEntry(m, models.AuditDelete, sound))
+		_, _ = s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("%v has been deleted\n", sound.Name))
+		return nil
+	}
+
+	if err := ctx.soundbiteModel.Trash(sound.Name); err != nil {
 		return err
 	}
 
-	err = sounds.DeleteFile(sound.FilePath)
-	if err != nil {
+	ctx.record(s, auditEntry(m, models.AuditDelete, sound))
+
+	msg := fmt.Sprintf("%v has been moved to the trash, bring it back with **%vrestore %v**",
+		sound.Name, ctx.botCfg.CommandPrefix, sound.Name)
+	_, _ = s.ChannelMessageSend(m.ChannelID, msg)
+
+	return nil
+}
+
+// Deletes a soundbite and its file for good. The file goes first so a failed delete
+// leaves a soundbite that can be deleted again rather than a file nothing refers to.
+func (ctx *Context) purgeSound(sound *models.Soundbite) error {
+	if err := deleteSoundFile(sound.FilePath); err != nil {
 		return err
 	}
 
-	_, _ = s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("%v has been deleted\n", name))
+	return ctx.soundbiteModel.ForceDelete(sound.Name)
+}
+
+// Deletes a soundbite's file, a file that is already gone is not an error
+func deleteSoundFile(path string) error {
+	// a missing file leaves nothing to clean up, orphans are caught by 'fsck'
+	err := sounds.DeleteFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
 
 	return nil
 }
@@ -187,32 +315,16 @@ func (ctx *Context) deleteCommand() func(*discordgo.Session, *discordgo.MessageC
 	}
 }
 
-// Bot will show all the sounds that available.
-func (ctx *Context) showSounds(s *discordgo.Session, m *discordgo.MessageCreate) error {
-	sounds, err := ctx.soundbiteModel.GetAll()
-	if err != nil {
-		return err
-	}
-
-	if len(sounds) == 0 {
-		_, _ = s.ChannelMessageSend(m.ChannelID, "there are no sounds :(")
-		return nil
-	}
-
-	var b strings.Builder
-	fmt.Fprint(&b, "**Available Sounds:** \n")
-	for _, sound := range sounds {
-		fmt.Fprintf(&b, "%v\n", sound.Name)
-	}
-
-	_, _ = s.ChannelMessageSend(m.ChannelID, b.String())
-	return nil
-}
-
 // Wrapper function for the 'sounds' command
 func (ctx *Context) soundsCommand() func(*discordgo.Session, *discordgo.MessageCreate, []string) error {
 	return func(s *discordgo.Session, m *discordgo.MessageCreate, st []string) error {
-		if err := ctx.showSounds(s, m); err != nil {
+		p, err := parseSoundsArgs(st)
+		if err != nil {
+			ctx.help(s, m, "sounds")
+			return err
+		}
+
+		if err := ctx.showSounds(s, m, p); err != nil {
 			return err
 		}
 
@@ -291,6 +403,10 @@ func (ctx *Context) upload(s *discordgo.Session, m *discordgo.MessageCreate, nam
 		return ErrNoAttachments
 	}
 
+	if err := ctx.checkQuota(s, m, 1, 0); err != nil {
+		return err
+	}
+
 	url := m.Attachments[0].URL
 	mp3, err := sounds.DownloadFileFromURL(name, url)
 	if err != nil {
@@ -309,11 +425,34 @@ func (ctx *Context) upload(s *discordgo.Session, m *discordgo.MessageCreate, nam
 		return err
 	}
 
-	_, err = ctx.soundbiteModel.Insert(name, m.Author.Username, m.Author.ID, f.Name(), hash)
+	length, err := sounds.DCADuration(f.Name())
 	if err != nil {
 		return err
 	}
 
+	if err := ctx.checkQuota(s, m, 1, length); err != nil {
+		sounds.DeleteFile(f.Name())
+		return err
+	}
+
+	soundbite := &models.Soundbite{
+		Name:        name,
+		Username:    m.Author.Username,
+		UserID:      m.Author.ID,
+		FilePath:    f.Name(),
+		FileHash:    hash,
+		Duration:    length,
+		SourceType:  models.SourceUpload,
+		SourceTitle: m.Attachments[0].Filename,
+	}
+
+	soundbite.ID, err = ctx.soundbiteModel.Insert(soundbite)
+	if err != nil {
+		return err
+	}
+
+	ctx.record(s, auditEntry(m, models.AuditCreate, soundbite))
+
 	ms := &discordgo.MessageSend{
 		Content: fmt.Sprintf("Your clip is ready. Play it with **!%v**", name),
 		Files:   []*discordgo.File{createDiscordFile(name, mp3)},
@@ -344,6 +483,20 @@ func (ctx *Context) uploadCommand() func(*discordgo.Session, *discordgo.MessageC
 }
 
 func (ctx *Context) rename(s *discordgo.Session, m *discordgo.MessageCreate, oldName, newName string) error {
+	sound, err := ctx.soundbiteModel.Get(oldName)
+	if err != nil {
+		return err
+	}
+
+	if err := ctx.authorizeSoundbite(s, m, sound); err != nil {
+		return err
+	}
+
+	return ctx.renameSound(s, m, oldName, newName)
+}
+
+// Renames a soundbite without checking who created it
+func (ctx *Context) renameSound(s *discordgo.Session, m *discordgo.MessageCreate, oldName, newName string) error {
 	err := ctx.soundbiteModel.UpdateName(oldName, newName)
 	if err != nil {
 		return err
@@ -354,9 +507,14 @@ func (ctx *Context) rename(s *discordgo.Session, m *discordgo.MessageCreate, old
 		return err
 	}
 
-	delete(ctx.soundbiteCache, oldName)
+	ctx.soundbiteCache.remove(oldName)
+	ctx.soundbiteCache.set(newName, sound)
+
+	e := auditEntry(m, models.AuditRename, sound)
+	e.Sound, e.OldValue, e.NewValue = oldName, oldName, newName
+	ctx.record(s, e)
 
-	ctx.soundbiteCache[newName] = sound
+	_, _ = s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("%v has been renamed to %v\n", oldName, newName))
 	return nil
 }
 
@@ -375,3 +533,119 @@ func (ctx *Context) renameCommand() func(*discordgo.Session, *discordgo.MessageC
 		return nil
 	}
 }
+
+// Checks the library for inconsistencies, logs them and optionally repairs them. Repairs are
+// added to the audit log as taken by the author of the message, or by the bot if there is none.
+func (ctx *Context) checkLibrary(s *discordgo.Session, m *discordgo.MessageCreate, repair bool) (*library.Report, error) {
+	r, err := library.Check(ctx.soundbiteModel, config.AUDIO_DIR)
+	if err != nil {
+		return nil, err
+	}
+
+	if repair {
+		if err := library.Repair(ctx.soundbiteModel, r); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, i := range r.Issues {
+		if i.Name != "" {
+			ctx.soundbiteCache.remove(i.Name)
+		}
+
+		ctx.infoLogger.Printf("library check: %v (repaired: %v)\n", i, i.Repaired)
+	}
+
+	ctx.recordRepairs(s, m, r)
+	return r, nil
+}
+
+// Bot will add the repaired issues of soundbites to the audit log, orphan
+// files don't belong to a soundbite so their repairs are only logged
+func (ctx *Context) recordRepairs(s *discordgo.Session, m *discordgo.MessageCreate, r *library.Report) {
+	for _, i := range r.Issues {
+		if !i.Repaired || i.Name == "" {
+			continue
+		}
+
+		e := &models.AuditEntry{Action: models.AuditRepair, SoundID: i.SoundID, Sound: i.Name, NewValue: i.Kind.String()}
+		if m != nil {
+			e.UserID, e.Username, e.GuildID = m.Author.ID, m.Author.Username, m.GuildID
+		}
+
+		ctx.record(s, e)
+	}
+}
+
+// Wrapper function for the 'fsck' command
+func (ctx *Context) fsckCommand() func(*discordgo.Session, *discordgo.MessageCreate, []string) error {
+	return func(s *discordgo.Session, m *discordgo.MessageCreate, st []string) error {
+		repair := len(st) > 0 && st[0] == "repair"
+		r, err := ctx.checkLibrary(s, m, repair)
+		if err != nil {
+			return err
+		}
+
+		_, _ = s.ChannelMessageSend(m.ChannelID, formatReport(r))
+		return nil
+	}
+}
+
+// Bot will give ownership of a soundbite to another user
+func (ctx *Context) transfer(s *discordgo.Session, m *discordgo.MessageCreate, name string, user *discordgo.User) error {
+	sound, err := ctx.soundbiteModel.Get(name)
+	if err != nil {
+		return err
+	}
+
+	err = ctx.soundbiteModel.UpdateOwner(name, user.Username, user.ID)
+	if err != nil {
+		return err
+	}
+
+	ctx.soundbiteCache.remove(name)
+
+	e := auditEntry(m, models.AuditTransfer, sound)
+	e.OldValue, e.NewValue = sound.Username, user.Username
+	ctx.record(s, e)
+
+	_, _ = s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("%v now belongs to <@%v>\n", name, user.ID))
+	return nil
+}
+
+// Wrapper function for the 'admin' command
+func (ctx *Context) adminCommand() func(*discordgo.Session, *discordgo.MessageCreate, []string) error {
+	return func(s *discordgo.Session, m *discordgo.MessageCreate, st []string) error {
+		if len(st) < 2 {
+			ctx.help(s, m, "admin")
+			return ErrNotEnoughArgs
+		}
+
+		switch st[0] {
+		case "delete":
+			sound, err := ctx.soundbiteModel.Get(st[1])
+			if err != nil {
+				return err
+			}
+
+			return ctx.removeSound(s, m, sound)
+		case "rename":
+			if len(st) < 3 {
+				ctx.help(s, m, "admin")
+				return ErrNotEnoughArgs
+			}
+
+			return ctx.renameSound(s, m, st[1], st[2])
+		case "transfer":
+			if len(m.Mentions) < 1 {
+				ctx.help(s, m, "admin")
+				return ErrNotEnoughArgs
+			}
+
+			return ctx.transfer(s, m, st[1], m.Mentions[0])
+		}
+
+		ctx.help(s, m, "admin")
+		return ErrInvalidSubcommand
+	}
+}
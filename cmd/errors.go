@@ -13,4 +13,15 @@ var (
 	ErrInvalidClipCommand = errors.New("clip needs at least a name and youtube link")
 	ErrNotEnoughArgs      = errors.New("command does not have enough arguments")
 	ErrNoAttachments      = errors.New("no attachments found in message")
+	ErrPermissionDenied   = errors.New("user does not have permission to use command")
+	ErrInvalidSubcommand  = errors.New("command does not have that subcommand")
+	ErrQuotaExceeded      = errors.New("user has exceeded their soundbite quota")
+	ErrInvalidTag         = errors.New("tag is empty, too long or contains a ':'")
+	ErrInvalidPeriod      = errors.New("period is not day, week, month, year or all")
+	ErrNoSource           = errors.New("soundbite has no source video to clip from")
+	ErrTooManySounds      = errors.New("combo has too many soundbites")
+	ErrInvalidChannel     = errors.New("channel is not a voice channel in this guild")
+	ErrInvalidSchedule    = errors.New("schedule id is not a number")
+	ErrTooManySchedules   = errors.New("user has too many schedules")
+	ErrInvalidAlias       = errors.New("alias has the name of a command")
 )
@@ -26,13 +26,18 @@ func (ctx *Context) messageCreate(s *discordgo.Session, m *discordgo.MessageCrea
 		return
 	}
 
-	if ctx.commands == nil {
-		ctx.commands = ctx.getCommands(ctx.botCfg.CommandPrefix)
-	}
-
 	c := parseCommand(m.Content)
 
 	if command, ok := ctx.commands[c.command]; ok {
+		if err := ctx.authorize(s, m, command.Permission); err != nil {
+			ctx.errorLogger.Println(err)
+			return
+		}
+
+		if !ctx.allow(s, m, command.Limiter, c.command) {
+			return
+		}
+
 		err := command.Action(s, m, c.args)
 		if err != nil {
 			ctx.errorLogger.Println(err)
@@ -40,7 +45,12 @@ func (ctx *Context) messageCreate(s *discordgo.Session, m *discordgo.MessageCrea
 		}
 	} else {
 		sl := strings.Split(c.command, ctx.botCfg.CommandPrefix)
-		soundName := sl[len(sl)-1]
+		soundName, err := ctx.resolveAlias(sl[len(sl)-1])
+		if err != nil {
+			ctx.errorLogger.Println(err)
+			return
+		}
+
 		exists, err := ctx.soundbiteModel.Exists(soundName, "")
 		if err != nil {
 			ctx.errorLogger.Println(err)
@@ -48,33 +58,55 @@ func (ctx *Context) messageCreate(s *discordgo.Session, m *discordgo.MessageCrea
 		}
 
 		if exists {
+			if !ctx.allow(s, m, ctx.playbackLimiter, "play") {
+				return
+			}
+
 			err := ctx.playSound(s, m, soundName)
 			if err != nil {
 				ctx.errorLogger.Println(err)
 				return
 			}
+		} else if soundName != "" {
+			if err := ctx.suggest(s, m, soundName); err != nil {
+				ctx.errorLogger.Println(err)
+				return
+			}
 		}
 	}
 }
 
-// Handler for when the bot joins or leaves a voice channel
+// Handler for when the bot or a member joins or leaves a voice channel
 func (ctx *Context) voiceStateChange(s *discordgo.Session, vs *discordgo.VoiceStateUpdate) {
 	if vs.VoiceState.UserID == ctx.botID {
-		ctx.isSpeaking = false
-
-		if vs.VoiceState.ChannelID == "" { // The bot disconnects from a voice channel
-			ctx.joinedVoice = false
-		} else { // the bot joins a voice channel
-			ctx.joinedVoice = true
-		}
+		// the bot joins a voice channel or disconnects from one
+		ctx.voice.setJoined(vs.VoiceState.ChannelID != "")
 	}
 
 	g, err := s.State.Guild(vs.GuildID)
 	if err != nil {
 		log.Fatal(err)
 	}
-	
-	if len(g.VoiceStates) == 1 && ctx.joinedVoice {
+
+	if _, joined := ctx.voice.connection(); len(g.VoiceStates) == 1 && joined {
 		ctx.leaveVoice(s, nil)
 	}
+
+	if err := ctx.playTheme(s, vs); err != nil {
+		ctx.errorLogger.Println(err)
+	}
+}
+
+// Handler for when a user clicks a button or picks an option on one of the bot's messages
+func (ctx *Context) interactionCreate(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if i.Type != discordgo.InteractionMessageComponent {
+		return
+	}
+
+	data := i.MessageComponentData()
+	if p, ok := parseSoundsPageID(data.CustomID, data.Values); ok {
+		if err := ctx.updateSounds(s, i, p); err != nil {
+			ctx.errorLogger.Println(err)
+		}
+	}
 }
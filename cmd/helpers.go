@@ -2,37 +2,73 @@ package main
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
+	"math"
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/tweekes0/pal-bot/config"
+	"github.com/tweekes0/pal-bot/internal/library"
 	"github.com/tweekes0/pal-bot/internal/models"
+	"github.com/tweekes0/pal-bot/internal/player"
+	"github.com/tweekes0/pal-bot/internal/ratelimit"
 	"github.com/tweekes0/pal-bot/internal/sounds"
 )
 
 // Descriptions for commands
 const (
-	pingDesc     = "Pong :D"
-	joinDesc     = "Joins to the user's current VoiceChannel"
-	leaveDesc    = "Leaves the current VoiceChannel"
-	clipDesc     = "Take a youtube video and create a soundbite from it. Soundbites cannot be longer than 10 seconds.  **!help clip** for more info."
-	deleteDesc   = "Delete a clipped soundbite the user created.  **!help delete** for more info."
-	soundsDesc   = "List all available sounds. Use **![SOUNDNAME]** to play soundbite"
-	commandsDesc = "List all available commands"
-	helpDesc     = "Get help and usage for specified commands"
-	uploadDesc   = "Upload an mp3 and create a sounbite from it"
-	renameDesc   = "Renames a soundbite"
+	pingDesc       = "Pong :D"
+	joinDesc       = "Joins to the user's current VoiceChannel"
+	leaveDesc      = "Leaves the current VoiceChannel"
+	clipDesc       = "Take a youtube video and create a soundbite from it. Soundbites cannot be longer than 10 seconds.  **!help clip** for more info."
+	deleteDesc     = "Delete a clipped soundbite the user created. Mods can delete any soundbite.  **!help delete** for more info."
+	soundsDesc     = "List all available sounds, or only those with a tag. Use **![SOUNDNAME]** to play soundbite.  **!help sounds** for more info."
+	commandsDesc   = "List all available commands"
+	helpDesc       = "Get help and usage for specified commands"
+	uploadDesc     = "Upload an mp3 and create a sounbite from it"
+	renameDesc     = "Renames a soundbite the user created. Mods can rename any soundbite"
+	fsckDesc       = "Check the soundbites for missing, orphaned and corrupt files. Admin only. **!help fsck** for more info."
+	adminDesc      = "Delete, rename or transfer any soundbite. Admin only. **!help admin** for more info."
+	quotaDesc      = "Shows how many soundbites the user has created and how many they can create"
+	tagDesc        = "Add tags to a soundbite.  **!help tag** for more info."
+	untagDesc      = "Remove tags from a soundbite.  **!help untag** for more info."
+	tagsDesc       = "List all tags and how many soundbites have them, or the tags of a soundbite"
+	searchDesc     = "Search soundbites by name, creator, tag or source video.  **!help search** for more info."
+	statsDesc      = "Shows how often a soundbite has been played and who played it most"
+	topDesc        = "Lists the most played soundbites.  **!help top** for more info."
+	mystatsDesc    = "Shows how many soundbites the user has played and their favorites"
+	introDesc      = "Set a soundbite to play when the user joins a VoiceChannel.  **!help intro** for more info."
+	outroDesc      = "Set a soundbite to play when the user leaves a VoiceChannel.  **!help outro** for more info."
+	themesDesc     = "Turn intros and outros on or off for the server. Mod only."
+	playDesc       = "Play a soundbite in the user's VoiceChannel or another VoiceChannel in the server.  **!help play** for more info."
+	stopDesc       = "Stops the soundbite that is playing and every one waiting to play"
+	skipDesc       = "Skips the soundbite that is playing so the next one can play"
+	pauseDesc      = "Pauses the soundbites that are playing until **!resume**"
+	resumeDesc     = "Resumes paused soundbites"
+	policyDesc     = "Set whether soundbites played at the same time queue, interrupt or mix. Mod only.  **!help policy** for more info."
+	randomDesc     = "Play a random soundbite, optionally with a tag or by a user.  **!help random** for more info."
+	comboDesc      = "Create and play combos of soundbites that play back-to-back.  **!help combo** for more info."
+	reclipDesc     = "Recreate a soundbite from its video with a new start time or duration.  **!help reclip** for more info."
+	infoDesc       = "Shows who created a soundbite, when and from where, with a preview.  **!help info** for more info."
+	scheduleDesc   = "Schedule a soundbite to play once or every week or day.  **!help schedule** for more info."
+	schedulesDesc  = "List the soundbites scheduled to play in the server"
+	unscheduleDesc = "Cancel a scheduled soundbite the user created. Mods can cancel any schedule.  **!help unschedule** for more info."
+	aliasDesc      = "Add alternate names that play a soundbite.  **!help alias** for more info."
+	trashDesc      = "List deleted soundbites that can still be restored"
+	restoreDesc    = "Bring back a deleted soundbite the user created. Mods can restore any soundbite.  **!help restore** for more info."
+	auditDesc      = "Show who created, renamed, deleted or changed soundbites. Mod only.  **!help audit** for more info."
 
 	clipHelp = `**!clip** [SOUNDNAME] [YOUTUBE_URL] <START_TIME>(optional) <DURATION>(optional)
 **Example:** !clip coolsound youtube.com/ID 00:23 5
 Creates a new sound called 'coolsound' that starts at 00:23 and is 5 seconds long`
 	deleteHelp = `**!delete** [SOUNDNAME]
 **Example:** !delete pika
-Deletes the soundbite the user created named 'pika'`
+Deletes the soundbite the user created named 'pika', it can be brought back with **!restore** until it is purged`
 	helpHelp = `**!help** [COMAND_NAME]
 **Example:** !help clip
 Displays Help information for the 'clip' command`
@@ -41,21 +77,117 @@ Displays Help information for the 'clip' command`
 Rename the 'jigglypuff' soundbite to 'jp'`
 	soundsHelp = `**![SOUNDNAME]** to play soundbite
 **Example:** !jigglypuff
-Plays the 'jigglypuff' soundbite`
+Plays the 'jigglypuff' soundbite
+**!sounds** <TAG>(optional) <name|newest|played|creator>(optional)
+**Example:** !sounds pokemon newest
+Lists the soundbites tagged 'pokemon', newest first`
+	tagHelp = `**!tag** [SOUNDNAME] [TAG...]
+**Example:** !tag jigglypuff pokemon anime
+Tags the 'jigglypuff' soundbite with 'pokemon' and 'anime'`
+	untagHelp = `**!untag** [SOUNDNAME] [TAG...]
+**Example:** !untag jigglypuff anime
+Removes the 'anime' tag from the 'jigglypuff' soundbite`
+	tagsHelp = `**!tags** <SOUNDNAME>(optional)
+**Example:** !tags jigglypuff
+Lists the tags of the 'jigglypuff' soundbite`
+	fsckHelp = `**!fsck** <repair>(optional)
+**Example:** !fsck repair
+Checks every soundbite's audio file and repairs any problems found.
+Soundbites with missing files are deleted, ones with corrupt files are moved to the trash and orphaned
+files are removed. Hash mismatches are only reported`
+	adminHelp = `**!admin delete** [SOUNDNAME]
+**!admin rename** [OLD_SOUNDNAME] [NEW_SOUNDNAME]
+**!admin transfer** [SOUNDNAME] [@USER]
+**Example:** !admin transfer jigglypuff @pal
+Gives ownership of the 'jigglypuff' soundbite to @pal`
+	searchHelp = `**!search** [QUERY]
+**Example:** !search jiggly
+Lists the soundbites whose name, creator, tags or source video title contain 'jiggly'
+or whose name, creator or tags closely match it`
+	statsHelp = `**!stats** [SOUNDNAME]
+**Example:** !stats jigglypuff
+Shows how many times 'jigglypuff' has been played, by how many users and when it was last played`
+	topHelp = `**!top** <day|week|month|year|all>(optional)
+**Example:** !top week
+Lists the most played soundbites of the last 7 days, every play is counted by default`
+	introHelp = `**!intro** <SOUNDNAME|off>(optional)
+**Example:** !intro jigglypuff
+Plays 'jigglypuff' whenever you join a VoiceChannel. **!intro off** removes it and **!intro** shows it`
+	outroHelp = `**!outro** <SOUNDNAME|off>(optional)
+**Example:** !outro jigglypuff
+Plays 'jigglypuff' to the people still there whenever you leave a VoiceChannel. **!outro off** removes it`
+	themesHelp = `**!themes** [on|off]
+**Example:** !themes off
+Stops intros and outros from playing in this server`
+	playHelp = `**!play** [SOUNDNAME] <#CHANNEL>(optional)
+**Example:** !play jigglypuff #General
+Plays 'jigglypuff' in the 'General' VoiceChannel even if you aren't in it, as long as you could speak there yourself`
+	policyHelp = `**!policy** <queue|interrupt|mix>(optional)
+**Example:** !policy mix
+Plays soundbites on top of each other when they are played at the same time.
+**queue** plays them one after another and **interrupt** stops the one playing. **!policy** shows the current policy`
+	randomHelp = `**!random** <TAG|@USER>(optional) <popular|rare>(optional)
+**Example:** !random pokemon rare
+Plays a random soundbite tagged 'pokemon', favoring the ones that have been played the least.
+**popular** favors the soundbites that have been played the most`
+	comboHelp = `**!combo** [COMBO_NAME]
+**!combo create** [COMBO_NAME] [SOUNDNAME...]
+**!combo update** [COMBO_NAME] [SOUNDNAME...]
+**!combo delete** [COMBO_NAME]
+**!combo show** [COMBO_NAME]
+**!combo list**
+**Example:** !combo create badjoke drumroll rimshot
+Creates the 'badjoke' combo that plays 'drumroll' and then 'rimshot', play it with **!combo badjoke**.
+Only the creator or a mod can update or delete a combo`
+	reclipHelp = `**!reclip** [SOUNDNAME] [START_TIME] <DURATION>(optional)
+**Example:** !reclip jigglypuff 00:07 5
+Recreates the 'jigglypuff' soundbite from its video starting at 00:07 and lasting 5 seconds.
+The soundbite keeps its current length if no duration is given, and keeps its tags and plays`
+	infoHelp = `**!info** [SOUNDNAME]
+**Example:** !info jigglypuff
+Shows the creator, created date, duration, source video and start time, file size,
+tags and play count of the 'jigglypuff' soundbite with an MP3 preview attached`
+	scheduleHelp = `**!schedule** [SOUNDNAME] [in DURATION|HH:MM|every DAY HH:MM] <#CHANNEL>(optional)
+**Example:** !schedule rimshot every friday 17:00 #General
+Plays 'rimshot' in the 'General' VoiceChannel every Friday at 17:00, or in your VoiceChannel if no channel is given.
+**in 10m** plays it in 10 minutes, **18:00** plays it at the next 18:00 and **every day 9:00** plays it daily.
+Times are in the bot's timezone`
+	unscheduleHelp = `**!unschedule** [ID]
+**Example:** !unschedule 3
+Cancels schedule 3, the IDs of schedules are shown by **!schedules**`
+	aliasHelp = `**!alias add** [ALIAS] [SOUNDNAME]
+**!alias remove** [ALIAS]
+**!alias list** <SOUNDNAME>(optional)
+**Example:** !alias add b bruh
+Plays the 'bruh' soundbite with **!b**. Only the creator of an alias or a mod can remove it`
+	restoreHelp = `**!restore** [SOUNDNAME]
+**Example:** !restore jigglypuff
+Takes the deleted 'jigglypuff' soundbite out of the trash with its tags and plays.
+Its name can't be used by another soundbite or alias while it is in the trash.
+Deleted soundbites are purged for good once they have been in the trash too long, **!trash** shows when`
+	auditHelp = `**!audit** <SOUNDNAME|@USER>(optional)
+**Example:** !audit jigglypuff
+Lists the newest changes to the 'jigglypuff' soundbite, including ones made under its old names.
+**!audit @USER** lists the changes a user made and **!audit** lists every change`
 	uploadHelp = `**!upload** [SOUNDNAME]
 **Example:** !upload jigglypuff
 Uploads an attached MP3 file and creates a soundbite named 'jigglypuff'`
 )
 
+const (
+	MAX_REPORT_ISSUES = 15 // The maximum number of issues listed in an 'fsck' reply
+)
+
 // Struct to structure command received from *discordgo.Message.Content
 type botCommand struct {
 	command string
 	args    []string
 }
+
 // Parses the specific command and any arguments that it may have
 func parseCommand(command string) *botCommand {
 	s := strings.Fields(command)
-	
+
 	if len(s) == 0 {
 		return nil
 	}
@@ -161,14 +293,17 @@ func parseClipCommand(args []string) (*clipArgs, error) {
 	return c, nil
 }
 
-// Gets the VoiceChannel of the user who sends a command,
-// will return nothing if the user is not in voice.
+// Gets the VoiceChannel of the user who sends a command in the message's guild,
+// will return nothing if the user is not in voice there.
 func getChannelID(s *discordgo.Session, m *discordgo.MessageCreate) string {
-	for _, guild := range s.State.Guilds {
-		for _, vs := range guild.VoiceStates {
-			if vs.UserID == m.Author.ID {
-				return vs.ChannelID
-			}
+	guild, err := s.State.Guild(m.GuildID)
+	if err != nil {
+		return ""
+	}
+
+	for _, vs := range guild.VoiceStates {
+		if vs.UserID == m.Author.ID {
+			return vs.ChannelID
 		}
 	}
 
@@ -216,6 +351,60 @@ func createFolder(path string) error {
 	return nil
 }
 
+// Checks whether a string slice contains a string
+func containsString(sl []string, s string) bool {
+	for _, v := range sl {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Creates a rate limiter from its config
+func newLimiter(cfg config.LimitConfig) *ratelimit.Limiter {
+	return ratelimit.New(cfg.Burst, time.Duration(cfg.Interval)*time.Second)
+}
+
+// Checks the author's rate limit for a command and lets them know
+// how long to wait if they have used it too often
+func (ctx *Context) allow(s *discordgo.Session, m *discordgo.MessageCreate, l *ratelimit.Limiter, command string) bool {
+	ok, wait := l.Allow(fmt.Sprint(m.Author.ID, ":", command))
+	if ok {
+		return true
+	}
+
+	secs := int(math.Ceil(wait.Seconds()))
+	msg := fmt.Sprintf("Slow down <@%v>, try again in %vs", m.Author.ID, secs)
+	_, _ = s.ChannelMessageSend(m.ChannelID, msg)
+
+	return false
+}
+
+// Formats the results of a library check into a message. Only the first
+// MAX_REPORT_ISSUES issues are listed to stay under discord's message limit.
+func formatReport(r *library.Report) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "**Checked %v soundbites, found %v issues", r.Checked, len(r.Issues))
+	if repaired := r.Repaired(); repaired > 0 {
+		fmt.Fprintf(&b, ", repaired %v", repaired)
+	}
+	fmt.Fprint(&b, "**\n")
+
+	for i, issue := range r.Issues {
+		if i == MAX_REPORT_ISSUES {
+			fmt.Fprintf(&b, "...and %v more\n", len(r.Issues)-i)
+			break
+		}
+
+		fmt.Fprintf(&b, "%v\n", issue)
+	}
+
+	return b.String()
+}
+
 // Returns a map of all 'Commands'
 func (ctx *Context) getCommands(prefix string) Commands {
 	commands := make(Commands)
@@ -238,6 +427,7 @@ func (ctx *Context) getCommands(prefix string) Commands {
 		Description: clipDesc,
 		Help:        clipHelp,
 		Action:      ctx.clipCommand(),
+		Limiter:     ctx.creationLimiter,
 	}
 	commands[fmt.Sprint(prefix, "delete")] = Command{
 		Description: deleteDesc,
@@ -263,38 +453,238 @@ func (ctx *Context) getCommands(prefix string) Commands {
 		Description: uploadDesc,
 		Help:        uploadHelp,
 		Action:      ctx.uploadCommand(),
+		Limiter:     ctx.creationLimiter,
 	}
 	commands[fmt.Sprint(prefix, "rename")] = Command{
 		Description: renameDesc,
 		Help:        renameHelp,
 		Action:      ctx.renameCommand(),
 	}
+	commands[fmt.Sprint(prefix, "fsck")] = Command{
+		Description: fsckDesc,
+		Help:        fsckHelp,
+		Action:      ctx.fsckCommand(),
+		Permission:  PermissionAdmin,
+	}
+	commands[fmt.Sprint(prefix, "tag")] = Command{
+		Description: tagDesc,
+		Help:        tagHelp,
+		Action:      ctx.tagCommand(),
+	}
+	commands[fmt.Sprint(prefix, "untag")] = Command{
+		Description: untagDesc,
+		Help:        untagHelp,
+		Action:      ctx.untagCommand(),
+	}
+	commands[fmt.Sprint(prefix, "tags")] = Command{
+		Description: tagsDesc,
+		Help:        tagsHelp,
+		Action:      ctx.tagsCommand(),
+	}
+	commands[fmt.Sprint(prefix, "search")] = Command{
+		Description: searchDesc,
+		Help:        searchHelp,
+		Action:      ctx.searchCommand(),
+	}
+	commands[fmt.Sprint(prefix, "intro")] = Command{
+		Description: introDesc,
+		Help:        introHelp,
+		Action:      ctx.introCommand(),
+	}
+	commands[fmt.Sprint(prefix, "outro")] = Command{
+		Description: outroDesc,
+		Help:        outroHelp,
+		Action:      ctx.outroCommand(),
+	}
+	commands[fmt.Sprint(prefix, "themes")] = Command{
+		Description: themesDesc,
+		Help:        themesHelp,
+		Action:      ctx.themesCommand(),
+		Permission:  PermissionMod,
+	}
+	commands[fmt.Sprint(prefix, "play")] = Command{
+		Description: playDesc,
+		Help:        playHelp,
+		Action:      ctx.playCommand(),
+		Limiter:     ctx.playbackLimiter,
+	}
+	commands[fmt.Sprint(prefix, "stop")] = Command{
+		Description: stopDesc,
+		Help:        stopDesc,
+		Action:      ctx.stopCommand(),
+	}
+	commands[fmt.Sprint(prefix, "skip")] = Command{
+		Description: skipDesc,
+		Help:        skipDesc,
+		Action:      ctx.skipCommand(),
+	}
+	commands[fmt.Sprint(prefix, "pause")] = Command{
+		Description: pauseDesc,
+		Help:        pauseDesc,
+		Action:      ctx.pauseCommand(),
+	}
+	commands[fmt.Sprint(prefix, "resume")] = Command{
+		Description: resumeDesc,
+		Help:        resumeDesc,
+		Action:      ctx.resumeCommand(),
+	}
+	commands[fmt.Sprint(prefix, "policy")] = Command{
+		Description: policyDesc,
+		Help:        policyHelp,
+		Action:      ctx.policyCommand(),
+		Permission:  PermissionMod,
+	}
+	commands[fmt.Sprint(prefix, "random")] = Command{
+		Description: randomDesc,
+		Help:        randomHelp,
+		Action:      ctx.randomCommand(),
+		Limiter:     ctx.playbackLimiter,
+	}
+	commands[fmt.Sprint(prefix, "combo")] = Command{
+		Description: comboDesc,
+		Help:        comboHelp,
+		Action:      ctx.comboCommand(),
+		Limiter:     ctx.playbackLimiter,
+	}
+	commands[fmt.Sprint(prefix, "schedule")] = Command{
+		Description: scheduleDesc,
+		Help:        scheduleHelp,
+		Action:      ctx.scheduleCommand(),
+	}
+	commands[fmt.Sprint(prefix, "schedules")] = Command{
+		Description: schedulesDesc,
+		Help:        schedulesDesc,
+		Action:      ctx.schedulesCommand(),
+	}
+	commands[fmt.Sprint(prefix, "unschedule")] = Command{
+		Description: unscheduleDesc,
+		Help:        unscheduleHelp,
+		Action:      ctx.unscheduleCommand(),
+	}
+	commands[fmt.Sprint(prefix, "alias")] = Command{
+		Description: aliasDesc,
+		Help:        aliasHelp,
+		Action:      ctx.aliasCommand(),
+	}
+	commands[fmt.Sprint(prefix, "trash")] = Command{
+		Description: trashDesc,
+		Help:        trashDesc,
+		Action:      ctx.trashCommand(),
+	}
+	commands[fmt.Sprint(prefix, "restore")] = Command{
+		Description: restoreDesc,
+		Help:        restoreHelp,
+		Action:      ctx.restoreCommand(),
+	}
+	commands[fmt.Sprint(prefix, "audit")] = Command{
+		Description: auditDesc,
+		Help:        auditHelp,
+		Action:      ctx.auditCommand(),
+		Permission:  PermissionMod,
+	}
+	commands[fmt.Sprint(prefix, "reclip")] = Command{
+		Description: reclipDesc,
+		Help:        reclipHelp,
+		Action:      ctx.reclipCommand(),
+		Limiter:     ctx.creationLimiter,
+	}
+	commands[fmt.Sprint(prefix, "info")] = Command{
+		Description: infoDesc,
+		Help:        infoHelp,
+		Action:      ctx.infoCommand(),
+	}
+	commands[fmt.Sprint(prefix, "stats")] = Command{
+		Description: statsDesc,
+		Help:        statsHelp,
+		Action:      ctx.statsCommand(),
+	}
+	commands[fmt.Sprint(prefix, "top")] = Command{
+		Description: topDesc,
+		Help:        topHelp,
+		Action:      ctx.topCommand(),
+	}
+	commands[fmt.Sprint(prefix, "mystats")] = Command{
+		Description: mystatsDesc,
+		Help:        mystatsDesc,
+		Action:      ctx.mystatsCommand(),
+	}
+	commands[fmt.Sprint(prefix, "quota")] = Command{
+		Description: quotaDesc,
+		Help:        quotaDesc,
+		Action:      ctx.quotaCommand(),
+	}
+	commands[fmt.Sprint(prefix, "admin")] = Command{
+		Description: adminDesc,
+		Help:        adminHelp,
+		Action:      ctx.adminCommand(),
+		Permission:  PermissionAdmin,
+	}
 
 	return commands
 }
 
-// Load and stream a soundbite into a VoiceChannel.
-func (ctx *Context) streamSoundBite(s *discordgo.Session, m *discordgo.MessageCreate, soundbite *models.Soundbite) error {
-	if err := ctx.joinVoice(s, m); err != nil {
-		return err
+// Load and stream soundbites into the author's VoiceChannel.
+func (ctx *Context) streamSoundBite(s *discordgo.Session, m *discordgo.MessageCreate, soundbites ...*models.Soundbite) error {
+	id := getChannelID(s, m)
+	if id == "" {
+		return ErrUserNotInVC
 	}
 
-	if ctx.isSpeaking {
-		return nil
+	err := ctx.streamToChannel(s, m.GuildID, id, m.Author.ID, soundbites...)
+	if errors.Is(err, player.ErrDisconnected) {
+		msg := fmt.Sprintf("Sorry <@%v>, the connection to voice was lost before your soundbite finished", m.Author.ID)
+		_, _ = s.ChannelMessageSend(m.ChannelID, msg)
 	}
 
-	buf, err := sounds.LoadSound(soundbite.FilePath)
+	return err
+}
+
+// Load and stream soundbites into a VoiceChannel back-to-back following the guild's playback
+// policy, each play is recorded for the given user.
+func (ctx *Context) streamToChannel(s *discordgo.Session, guildID, channelID, uid string, soundbites ...*models.Soundbite) error {
+	pb, err := ctx.joinChannel(s, guildID, channelID)
 	if err != nil {
 		return err
 	}
+	p, idle := pb.player, pb.idle
 
-	ctx.vc.Speaking(true)
-	ctx.isSpeaking = true
-	for _, b := range buf {
-		ctx.vc.OpusSend <- b
+	settings, err := ctx.settingsModel.Get(guildID)
+	if err != nil {
+		return err
+	}
+
+	if policy, ok := player.ParsePolicy(settings.PlaybackPolicy); ok {
+		p.SetPolicy(policy)
+	}
+
+	// every soundbite is loaded before playing so there are no gaps between them
+	var buf [][]byte
+	for _, soundbite := range soundbites {
+		frames, err := sounds.LoadSound(soundbite.FilePath)
+		if err != nil {
+			return err
+		}
+
+		buf = append(buf, frames...)
+	}
+
+	idle.Start()
+	defer idle.Done()
+
+	// sounds that are stopped, skipped, interrupted or cut off by the bot leaving are not counted as plays
+	if err := <-p.Play(buf); err != nil {
+		if errors.Is(err, player.ErrDisconnected) {
+			return err
+		}
+
+		return nil
+	}
+
+	for _, soundbite := range soundbites {
+		if err := ctx.playModel.Record(soundbite.ID, uid, guildID); err != nil {
+			ctx.errorLogger.Println(err)
+		}
 	}
 
-	ctx.vc.Speaking(false)
-	ctx.isSpeaking = false
 	return nil
 }
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/tweekes0/pal-bot/internal/models"
+	"github.com/tweekes0/pal-bot/internal/sounds"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Formats an offset into a video as (hh:)mm:ss
+func formatOffset(d time.Duration) string {
+	d = d.Round(time.Second)
+	h, m, sec := int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60
+
+	if h > 0 {
+		return fmt.Sprintf("%02d:%02d:%02d", h, m, sec)
+	}
+
+	return fmt.Sprintf("%02d:%02d", m, sec)
+}
+
+// Formats a file size in bytes as B, KB or MB
+func formatSize(size int64) string {
+	switch {
+	case size >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(size)/(1<<20))
+	case size >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(size)/(1<<10))
+	}
+
+	return fmt.Sprintf("%v B", size)
+}
+
+// Formats where a soundbite's audio came from and who to credit for it
+func formatSource(sound *models.Soundbite) string {
+	switch sound.SourceType {
+	case models.SourceUpload:
+		if sound.SourceTitle == "" {
+			return "Uploaded"
+		}
+
+		return fmt.Sprintf("Uploaded as %v", sound.SourceTitle)
+	case models.SourceYoutube:
+		title := sound.SourceTitle
+		if title == "" {
+			title = sound.SourceURL
+		}
+
+		source := fmt.Sprintf("[%v](%v) from %v", title, sound.SourceURL, formatOffset(sound.Start))
+		if sound.SourceAuthor != "" {
+			source += fmt.Sprintf("\nUploaded by %v", sound.SourceAuthor)
+		}
+
+		return source
+	}
+
+	// soundbites created before sources were recorded may still have their video
+	if sound.SourceURL != "" {
+		return fmt.Sprintf("%v from %v", sound.SourceURL, formatOffset(sound.Start))
+	}
+
+	return "Unknown"
+}
+
+// Bot will show who created a soundbite, when and from where, with an MP3 preview attached
+func (ctx *Context) info(s *discordgo.Session, m *discordgo.MessageCreate, name string) error {
+	sound, err := ctx.soundbiteModel.Get(name)
+	if err != nil {
+		return err
+	}
+
+	tags, err := ctx.tagModel.Get(sound.Name)
+	if err != nil {
+		return err
+	}
+
+	plays, err := ctx.playModel.Count(sound.ID)
+	if err != nil {
+		return err
+	}
+
+	size := "missing"
+	if fi, err := os.Stat(sound.FilePath); err == nil {
+		size = formatSize(fi.Size())
+	}
+
+	source := formatSource(sound)
+
+	tagList := "none"
+	if len(tags) > 0 {
+		tagList = strings.Join(tags, ", ")
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title: fmt.Sprintf("%v%v", ctx.botCfg.CommandPrefix, sound.Name),
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Creator", Value: sound.Username, Inline: true},
+			{Name: "Created", Value: fmt.Sprintf("<t:%v:D>", sound.Created.Unix()), Inline: true},
+			{Name: "Duration", Value: fmt.Sprintf("%.1fs", sound.Duration.Seconds()), Inline: true},
+			{Name: "Plays", Value: fmt.Sprint(plays), Inline: true},
+			{Name: "File size", Value: size, Inline: true},
+			{Name: "Tags", Value: tagList, Inline: true},
+			{Name: "Source", Value: source},
+		},
+	}
+
+	ms := &discordgo.MessageSend{
+		Embeds: []*discordgo.MessageEmbed{embed},
+	}
+
+	// the preview is optional, the info is still useful without it
+	mp3, err := sounds.DCAToMP3(sound.FilePath)
+	if err != nil {
+		ctx.errorLogger.Println(err)
+	} else {
+		defer sounds.DeleteFile(mp3.Name())
+		ms.Files = []*discordgo.File{createDiscordFile(sound.Name, mp3)}
+	}
+
+	_, err = s.ChannelMessageSendComplex(m.ChannelID, ms)
+	return err
+}
+
+// Wrapper function for the 'info' command
+func (ctx *Context) infoCommand() func(*discordgo.Session, *discordgo.MessageCreate, []string) error {
+	return func(s *discordgo.Session, m *discordgo.MessageCreate, st []string) error {
+		if len(st) < 1 {
+			ctx.help(s, m, "info")
+			return ErrNotEnoughArgs
+		}
+
+		return ctx.info(s, m, st[0])
+	}
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/tweekes0/pal-bot/internal/models"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	SOUNDS_PER_PAGE = 20 // The number of soundbites shown on each page of the 'sounds' listing
+
+	soundsPageID = "sounds"      // CustomID prefix of the 'sounds' listing's page buttons
+	soundsSortID = "sounds-sort" // CustomID prefix of the 'sounds' listing's sort menu
+)
+
+// Struct for a sort order option of the 'sounds' listing
+type sortOption struct {
+	name  string
+	label string
+	order models.SortOrder
+}
+
+// Sort order options in the order they are shown in the sort menu
+var sortOptions = []sortOption{
+	{name: "name", label: "Name", order: models.SortByName},
+	{name: "newest", label: "Newest", order: models.SortByNewest},
+	{name: "played", label: "Most played", order: models.SortByPlays},
+	{name: "creator", label: "Creator", order: models.SortByCreator},
+}
+
+// Finds a sort order option by its name
+func findSortOption(name string) (sortOption, bool) {
+	for _, o := range sortOptions {
+		if o.name == name {
+			return o, true
+		}
+	}
+
+	return sortOption{}, false
+}
+
+// Struct for the state of a 'sounds' listing. The state is stored in the
+// CustomID of the listing's components so no state is kept by the bot.
+type soundsPage struct {
+	page int
+	sort string
+	tag  string
+}
+
+// Parses the args of the 'sounds' command, an arg that is a sort order option sets
+// the sort order and any other arg is the tag to filter by. Returns ErrInvalidTag if
+// the tag could not be stored in the listing's CustomIDs.
+func parseSoundsArgs(args []string) (soundsPage, error) {
+	p := soundsPage{sort: sortOptions[0].name}
+	for _, arg := range args {
+		if _, ok := findSortOption(arg); ok {
+			p.sort = arg
+			continue
+		}
+
+		tags, err := parseTags([]string{arg})
+		if err != nil {
+			return soundsPage{}, err
+		}
+
+		p.tag = tags[0]
+	}
+
+	return p, nil
+}
+
+// Parses the CustomID and values of a component on a 'sounds' listing
+func parseSoundsPageID(customID string, values []string) (soundsPage, bool) {
+	// the tag is last so it is kept whole even if it contains a ':'
+	sort := strings.SplitN(customID, ":", 2)
+	page := strings.SplitN(customID, ":", 4)
+
+	switch {
+	case sort[0] == soundsSortID && len(sort) == 2 && len(values) > 0:
+		return soundsPage{sort: values[0], tag: sort[1]}, true
+	case page[0] == soundsPageID && len(page) == 4:
+		n, err := strconv.Atoi(page[1])
+		if err != nil {
+			return soundsPage{}, false
+		}
+
+		return soundsPage{page: n, sort: page[2], tag: page[3]}, true
+	}
+
+	return soundsPage{}, false
+}
+
+// Creates the CustomID of a button that shows another page of the listing
+func (p soundsPage) pageID(page int) string {
+	return fmt.Sprintf("%v:%v:%v:%v", soundsPageID, page, p.sort, p.tag)
+}
+
+// Creates the embed and components for a page of the 'sounds' listing.
+// Returns nil if there are no sounds to list.
+func (ctx *Context) renderSoundsPage(p soundsPage) ([]*discordgo.MessageEmbed, []discordgo.MessageComponent, error) {
+	option, ok := findSortOption(p.sort)
+	if !ok {
+		option = sortOptions[0]
+		p.sort = option.name
+	}
+
+	if p.page < 0 {
+		p.page = 0
+	}
+
+	opts := models.ListOptions{
+		Tag:    p.tag,
+		Sort:   option.order,
+		Offset: p.page * SOUNDS_PER_PAGE,
+		Limit:  SOUNDS_PER_PAGE,
+	}
+
+	sounds, total, err := ctx.soundbiteModel.List(opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if total == 0 {
+		return nil, nil, nil
+	}
+
+	// the page no longer exists if sounds were deleted since it was shown
+	pages := (total + SOUNDS_PER_PAGE - 1) / SOUNDS_PER_PAGE
+	if p.page >= pages {
+		p.page = pages - 1
+		opts.Offset = p.page * SOUNDS_PER_PAGE
+
+		sounds, total, err = ctx.soundbiteModel.List(opts)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var b strings.Builder
+	for _, sound := range sounds {
+		fmt.Fprintf(&b, "**%v%v** · %v\n", ctx.botCfg.CommandPrefix, sound.Name, sound.Username)
+	}
+
+	title := "Available Sounds"
+	if p.tag != "" {
+		title = fmt.Sprintf("Available Sounds tagged %v", p.tag)
+	}
+
+	first := p.page*SOUNDS_PER_PAGE + 1
+	embed := &discordgo.MessageEmbed{
+		Title:       title,
+		Description: b.String(),
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("Page %v/%v · Showing %v-%v of %v sounds · Sorted by %v",
+				p.page+1, pages, first, first+len(sounds)-1, total, strings.ToLower(option.label)),
+		},
+	}
+
+	menu := discordgo.SelectMenu{
+		CustomID:    fmt.Sprintf("%v:%v", soundsSortID, p.tag),
+		Placeholder: "Sort by",
+	}
+	for _, o := range sortOptions {
+		menu.Options = append(menu.Options, discordgo.SelectMenuOption{
+			Label:   o.label,
+			Value:   o.name,
+			Default: o.name == p.sort,
+		})
+	}
+
+	components := []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    "Previous",
+					Style:    discordgo.SecondaryButton,
+					CustomID: p.pageID(p.page - 1),
+					Disabled: p.page == 0,
+				},
+				discordgo.Button{
+					Label:    "Next",
+					Style:    discordgo.SecondaryButton,
+					CustomID: p.pageID(p.page + 1),
+					Disabled: p.page >= pages-1,
+				},
+			},
+		},
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{menu},
+		},
+	}
+
+	return []*discordgo.MessageEmbed{embed}, components, nil
+}
+
+// Bot will show the first page of the 'sounds' listing
+func (ctx *Context) showSounds(s *discordgo.Session, m *discordgo.MessageCreate, p soundsPage) error {
+	embeds, components, err := ctx.renderSoundsPage(p)
+	if err != nil {
+		return err
+	}
+
+	if embeds == nil {
+		_, _ = s.ChannelMessageSend(m.ChannelID, "there are no sounds :(")
+		return nil
+	}
+
+	ms := &discordgo.MessageSend{
+		Embeds:     embeds,
+		Components: components,
+	}
+
+	_, err = s.ChannelMessageSendComplex(m.ChannelID, ms)
+	return err
+}
+
+// Bot will show another page or sort order of a 'sounds' listing by editing its message
+func (ctx *Context) updateSounds(s *discordgo.Session, i *discordgo.InteractionCreate, p soundsPage) error {
+	embeds, components, err := ctx.renderSoundsPage(p)
+	if err != nil {
+		return err
+	}
+
+	data := &discordgo.InteractionResponseData{
+		Embeds:     embeds,
+		Components: components,
+	}
+
+	if embeds == nil {
+		data.Content = "there are no sounds :("
+		data.Embeds = []*discordgo.MessageEmbed{}
+		data.Components = []discordgo.MessageComponent{}
+	}
+
+	return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: data,
+	})
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	test "github.com/tweekes0/pal-bot/internal/testing"
+)
+
+func TestParseSoundsArgs(t *testing.T) {
+	tt := []struct {
+		description string
+		input       []string
+		expected    soundsPage
+		expectedErr error
+	}{
+		{"no args", []string{}, soundsPage{sort: "name"}, nil},
+		{"sort and tag", []string{"newest", "#Memes"}, soundsPage{sort: "newest", tag: "memes"}, nil},
+		{"tag too long", []string{strings.Repeat("a", MAX_TAG_LENGTH+1)}, soundsPage{}, ErrInvalidTag},
+		{"tag with a colon", []string{"a:b"}, soundsPage{}, ErrInvalidTag},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseSoundsArgs(tc.input)
+			test.AssertError(t, err, tc.expectedErr)
+			test.AssertType(t, got, tc.expected)
+		})
+	}
+}
+
+func TestParseSoundsPageID(t *testing.T) {
+	expected := soundsPage{page: 3, sort: "played", tag: strings.Repeat("a", MAX_TAG_LENGTH)}
+
+	t.Run("page buttons", func(t *testing.T) {
+		id := expected.pageID(expected.page)
+		if len(id) > 100 {
+			t.Errorf("expected a CustomID of at most 100 characters, got %v", len(id))
+		}
+
+		got, ok := parseSoundsPageID(id, nil)
+		test.AssertType(t, ok, true)
+		test.AssertType(t, got, expected)
+	})
+
+	t.Run("sort menu", func(t *testing.T) {
+		id := fmt.Sprintf("%v:%v", soundsSortID, expected.tag)
+
+		got, ok := parseSoundsPageID(id, []string{"newest"})
+		test.AssertType(t, ok, true)
+		test.AssertType(t, got, soundsPage{sort: "newest", tag: expected.tag})
+
+		_, ok = parseSoundsPageID(id, nil)
+		test.AssertType(t, ok, false)
+	})
+
+	t.Run("unknown components", func(t *testing.T) {
+		_, ok := parseSoundsPageID("sounds:x:name:", nil)
+		test.AssertType(t, ok, false)
+
+		_, ok = parseSoundsPageID("other:1", []string{"name"})
+		test.AssertType(t, ok, false)
+	})
+}
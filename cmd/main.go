@@ -8,26 +8,38 @@ import (
 	"syscall"
 
 	"github.com/tweekes0/pal-bot/config"
+	"github.com/tweekes0/pal-bot/internal/clock"
+	"github.com/tweekes0/pal-bot/internal/library"
 	"github.com/tweekes0/pal-bot/internal/models"
-
-	"github.com/bwmarrin/discordgo"
+	"github.com/tweekes0/pal-bot/internal/ratelimit"
+	"github.com/tweekes0/pal-bot/internal/scheduler"
 )
 
-type soundCache map[string]*models.Soundbite
-
 // Struct that holds the bot's loggers and state necessary
 // to control the bot
 type Context struct {
-	botID          string
-	botCfg         *config.BotConfig
-	commands       Commands
-	errorLogger    *log.Logger
-	infoLogger     *log.Logger
-	vc             *discordgo.VoiceConnection
-	soundbiteModel *models.SoundbiteModel
-	joinedVoice    bool
-	isSpeaking     bool
-	soundbiteCache soundCache
+	botID           string
+	botCfg          *config.BotConfig
+	commands        Commands
+	errorLogger     *log.Logger
+	infoLogger      *log.Logger
+	voice           voiceState
+	clock           clock.Clock
+	soundbiteModel  *models.SoundbiteModel
+	tagModel        *models.TagModel
+	playModel       *models.PlayModel
+	searchModel     *models.SearchModel
+	settingsModel   *models.SettingsModel
+	themeModel      *models.ThemeModel
+	playlistModel   *models.PlaylistModel
+	scheduleModel   *models.ScheduleModel
+	aliasModel      *models.AliasModel
+	auditModel      *models.AuditModel
+	scheduler       *scheduler.Scheduler
+	soundbiteCache  *soundCache
+	playbackLimiter *ratelimit.Limiter
+	creationLimiter *ratelimit.Limiter
+	themeLimiter    *ratelimit.Limiter
 }
 
 func main() {
@@ -74,16 +86,40 @@ func main() {
 	}
 
 	ctx := &Context{
-		joinedVoice:    false,
-		isSpeaking:     false,
 		botID:          botID,
 		botCfg:         cfg,
+		clock:          clock.New(),
 		errorLogger:    errLog,
 		infoLogger:     infoLog,
 		soundbiteModel: &models.SoundbiteModel{DB: db},
+		tagModel:       &models.TagModel{DB: db},
+		playModel:      &models.PlayModel{DB: db},
+		searchModel:    &models.SearchModel{DB: db},
+		settingsModel:  &models.SettingsModel{DB: db},
+		themeModel:     &models.ThemeModel{DB: db},
+		playlistModel:  &models.PlaylistModel{DB: db},
+		scheduleModel:  &models.ScheduleModel{DB: db},
+		aliasModel:     &models.AliasModel{DB: db},
+		auditModel:     &models.AuditModel{DB: db},
 	}
 
+	ctx.playbackLimiter = newLimiter(cfg.RateLimit.Playback)
+	ctx.creationLimiter = newLimiter(cfg.RateLimit.Creation)
+	ctx.themeLimiter = newLimiter(config.LimitConfig{Burst: 1, Interval: cfg.Themes.Cooldown})
+
 	ctx.soundbiteModel.Initialize()
+	ctx.tagModel.Initialize()
+	ctx.playModel.Initialize()
+	ctx.settingsModel.Initialize()
+	ctx.themeModel.Initialize()
+	ctx.playlistModel.Initialize()
+	ctx.scheduleModel.Initialize()
+	ctx.aliasModel.Initialize()
+	ctx.auditModel.Initialize()
+
+	if err := ctx.searchModel.Initialize(); err != nil {
+		errLog.Fatalln(err)
+	}
 
 	// Create a cache of all the soundbites in the db
 	// soundbiteCache, err := ctx.createSoundsCache()
@@ -91,10 +127,43 @@ func main() {
 	// 	errLog.Fatalln(err)
 	// }
 
-	ctx.soundbiteCache = soundCache{}
+	ctx.soundbiteCache = newSoundCache()
+	ctx.commands = ctx.getCommands(cfg.CommandPrefix)
+
+	// Soundbites created before durations were stored count towards quotas once backfilled
+	if _, err := library.BackfillDurations(ctx.soundbiteModel); err != nil {
+		errLog.Println(err)
+	}
+
+	if cfg.Library.CheckOnStartup || cfg.Library.RepairOnStartup {
+		r, err := ctx.checkLibrary(bot, nil, cfg.Library.RepairOnStartup)
+		if err != nil {
+			errLog.Fatalln(err)
+		}
+
+		infoLog.Printf("Library check found %v issues in %v soundbites\n", len(r.Issues), r.Checked)
+	}
+
+	// Soundbites deleted while the bot was offline are purged as well
+	if cfg.Trash.Retention > 0 {
+		if _, err := ctx.purgeTrash(bot); err != nil {
+			errLog.Println(err)
+		}
+
+		ctx.schedulePurge(bot)
+	}
+
+	// Schedules saved before a restart carry on playing
+	ctx.scheduler = scheduler.New(ctx.clock, func(id int) { ctx.runSchedule(bot, id) })
+	defer ctx.scheduler.Stop()
+
+	if err := ctx.loadSchedules(); err != nil {
+		errLog.Println(err)
+	}
 
 	bot.AddHandler(ctx.messageCreate)
 	bot.AddHandler(ctx.voiceStateChange)
+	bot.AddHandler(ctx.interactionCreate)
 
 	infoLog.Println("Bot is now running. Press CTRL-C to exit")
 
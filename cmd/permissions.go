@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/tweekes0/pal-bot/config"
+	"github.com/tweekes0/pal-bot/internal/models"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// The level of access a user needs to use a command
+type Permission int
+
+const (
+	PermissionEveryone Permission = iota
+	PermissionMod
+	PermissionAdmin
+)
+
+func (p Permission) String() string {
+	switch p {
+	case PermissionMod:
+		return "mod"
+	case PermissionAdmin:
+		return "admin"
+	}
+
+	return "everyone"
+}
+
+// Checks whether the author of a message has the Administrator permission in the guild.
+// Guild owners always have every permission.
+func isGuildAdmin(s *discordgo.Session, m *discordgo.MessageCreate) bool {
+	perms, err := s.UserChannelPermissions(m.Author.ID, m.ChannelID)
+	if err != nil {
+		return false
+	}
+
+	return perms&discordgo.PermissionAdministrator != 0
+}
+
+// Gets the permission level of a member based on the admin and mod roles configured
+// for their guild, admin roles outrank mod roles. A nil member has no roles.
+func memberPermission(guild config.GuildConfig, member *discordgo.Member) Permission {
+	if member == nil {
+		return PermissionEveryone
+	}
+
+	level := PermissionEveryone
+	for _, role := range member.Roles {
+		if containsString(guild.AdminRoleIDs, role) {
+			return PermissionAdmin
+		}
+
+		if containsString(guild.ModRoleIDs, role) {
+			level = PermissionMod
+		}
+	}
+
+	return level
+}
+
+// Gets the permission level of the author of a message based on the
+// admin and mod roles configured for the message's guild
+func (ctx *Context) permissionLevel(s *discordgo.Session, m *discordgo.MessageCreate) Permission {
+	if isGuildAdmin(s, m) {
+		return PermissionAdmin
+	}
+
+	return memberPermission(ctx.botCfg.Guilds[m.GuildID], m.Member)
+}
+
+// Checks that the author of a message has at least the required permission
+// and lets them know if they do not
+func (ctx *Context) authorize(s *discordgo.Session, m *discordgo.MessageCreate, required Permission) error {
+	if ctx.permissionLevel(s, m) >= required {
+		return nil
+	}
+
+	msg := fmt.Sprintf("Sorry <@%v>, only a %v can do that", m.Author.ID, required)
+	_, _ = s.ChannelMessageSend(m.ChannelID, msg)
+
+	return ErrPermissionDenied
+}
+
+// Checks that the author of a message created the soundbite or is a mod
+// and lets them know if they are neither
+func (ctx *Context) authorizeSoundbite(s *discordgo.Session, m *discordgo.MessageCreate, sound *models.Soundbite) error {
+	return ctx.authorizeOwner(s, m, sound.Name, sound.UserID, sound.Username)
+}
+
+// Checks that the author of a message owns the named item or is a mod
+// and lets them know if they are neither
+func (ctx *Context) authorizeOwner(s *discordgo.Session, m *discordgo.MessageCreate, name, ownerID, owner string) error {
+	if ownerID == m.Author.ID || ctx.permissionLevel(s, m) >= PermissionMod {
+		return nil
+	}
+
+	msg := fmt.Sprintf("Sorry <@%v>, **%v** belongs to %v", m.Author.ID, name, owner)
+	_, _ = s.ChannelMessageSend(m.ChannelID, msg)
+
+	return models.ErrCommandOwnership
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/tweekes0/pal-bot/config"
+	test "github.com/tweekes0/pal-bot/internal/testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestMemberPermission(t *testing.T) {
+	guild := config.GuildConfig{
+		AdminRoleIDs: []string{"admin"},
+		ModRoleIDs:   []string{"mod", "helper"},
+	}
+
+	tt := []struct {
+		description string
+		guild       config.GuildConfig
+		member      *discordgo.Member
+		expected    Permission
+	}{
+		{"no member", guild, nil, PermissionEveryone},
+		{"no roles", guild, &discordgo.Member{}, PermissionEveryone},
+		{"unconfigured roles", guild, &discordgo.Member{Roles: []string{"gamer"}}, PermissionEveryone},
+		{"mod role", guild, &discordgo.Member{Roles: []string{"gamer", "helper"}}, PermissionMod},
+		{"admin role", guild, &discordgo.Member{Roles: []string{"admin"}}, PermissionAdmin},
+		{"admin role after mod role", guild, &discordgo.Member{Roles: []string{"mod", "admin"}}, PermissionAdmin},
+		{"mod role after admin role", guild, &discordgo.Member{Roles: []string{"admin", "mod"}}, PermissionAdmin},
+		{"unconfigured guild", config.GuildConfig{}, &discordgo.Member{Roles: []string{"admin"}}, PermissionEveryone},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			got := memberPermission(tc.guild, tc.member)
+			test.AssertType(t, got, tc.expected)
+		})
+	}
+}
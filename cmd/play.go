@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Gets the channel ID from a channel mention such as <#1234>
+func parseChannelMention(arg string) (string, bool) {
+	if !strings.HasPrefix(arg, "<#") || !strings.HasSuffix(arg, ">") {
+		return "", false
+	}
+
+	id := strings.TrimSuffix(strings.TrimPrefix(arg, "<#"), ">")
+	return id, id != ""
+}
+
+// Checks that a channel is a VoiceChannel in the message's guild that the author could
+// connect and speak in themselves, and lets the author know if it is not
+func (ctx *Context) authorizeChannel(s *discordgo.Session, m *discordgo.MessageCreate, channelID string) error {
+	channel, err := s.State.Channel(channelID)
+	if err != nil {
+		channel, err = s.Channel(channelID)
+	}
+
+	if err != nil || channel.GuildID != m.GuildID || channel.Type != discordgo.ChannelTypeGuildVoice {
+		msg := fmt.Sprintf("Sorry <@%v>, <#%v> isn't a VoiceChannel in this server", m.Author.ID, channelID)
+		_, _ = s.ChannelMessageSend(m.ChannelID, msg)
+		return ErrInvalidChannel
+	}
+
+	perms, err := s.UserChannelPermissions(m.Author.ID, channelID)
+	if err != nil {
+		return err
+	}
+
+	needed := int64(discordgo.PermissionVoiceConnect | discordgo.PermissionVoiceSpeak)
+	if perms&needed != needed {
+		msg := fmt.Sprintf("Sorry <@%v>, you can't speak in <#%v>", m.Author.ID, channelID)
+		_, _ = s.ChannelMessageSend(m.ChannelID, msg)
+		return ErrPermissionDenied
+	}
+
+	return nil
+}
+
+// Bot will play a soundbite in a VoiceChannel of the message's guild, the author doesn't have to be in it
+func (ctx *Context) playSoundIn(s *discordgo.Session, m *discordgo.MessageCreate, name, channelID string) error {
+	if err := ctx.authorizeChannel(s, m, channelID); err != nil {
+		return err
+	}
+
+	soundbite, err := ctx.getSoundbite(name)
+	if err != nil {
+		return err
+	}
+
+	return ctx.streamToChannel(s, m.GuildID, channelID, m.Author.ID, soundbite)
+}
+
+// Wrapper function for the 'play' command
+func (ctx *Context) playCommand() func(*discordgo.Session, *discordgo.MessageCreate, []string) error {
+	return func(s *discordgo.Session, m *discordgo.MessageCreate, st []string) error {
+		if len(st) < 1 {
+			ctx.help(s, m, "play")
+			return ErrNotEnoughArgs
+		}
+
+		name, err := ctx.resolveAlias(st[0])
+		if err != nil {
+			return err
+		}
+
+		if len(st) < 2 {
+			return ctx.playSound(s, m, name)
+		}
+
+		channelID, ok := parseChannelMention(st[1])
+		if !ok {
+			ctx.help(s, m, "play")
+			return ErrInvalidChannel
+		}
+
+		return ctx.playSoundIn(s, m, name, channelID)
+	}
+}
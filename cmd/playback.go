@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/tweekes0/pal-bot/internal/player"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Bot will run a playback control on the player of its voice connection, the
+// control returns false when there was nothing for it to do
+func (ctx *Context) controlPlayback(s *discordgo.Session, m *discordgo.MessageCreate, control func(*player.Player) bool, done, failed string) error {
+	p := ctx.voice.current()
+	if p == nil || !control(p.player) {
+		_, _ = s.ChannelMessageSend(m.ChannelID, failed)
+		return nil
+	}
+
+	_, _ = s.ChannelMessageSend(m.ChannelID, done)
+	return nil
+}
+
+// Wrapper function for the 'stop' command
+func (ctx *Context) stopCommand() func(*discordgo.Session, *discordgo.MessageCreate, []string) error {
+	return func(s *discordgo.Session, m *discordgo.MessageCreate, st []string) error {
+		return ctx.controlPlayback(s, m, (*player.Player).Stop,
+			"Stopped every soundbite", "Nothing is playing")
+	}
+}
+
+// Wrapper function for the 'skip' command
+func (ctx *Context) skipCommand() func(*discordgo.Session, *discordgo.MessageCreate, []string) error {
+	return func(s *discordgo.Session, m *discordgo.MessageCreate, st []string) error {
+		return ctx.controlPlayback(s, m, (*player.Player).Skip,
+			"Skipped to the next soundbite", "Nothing is playing")
+	}
+}
+
+// Wrapper function for the 'pause' command
+func (ctx *Context) pauseCommand() func(*discordgo.Session, *discordgo.MessageCreate, []string) error {
+	return func(s *discordgo.Session, m *discordgo.MessageCreate, st []string) error {
+		done := fmt.Sprintf("Paused, **%vresume** to carry on", ctx.botCfg.CommandPrefix)
+		return ctx.controlPlayback(s, m, (*player.Player).Pause, done, "Nothing is playing or it is already paused")
+	}
+}
+
+// Wrapper function for the 'resume' command
+func (ctx *Context) resumeCommand() func(*discordgo.Session, *discordgo.MessageCreate, []string) error {
+	return func(s *discordgo.Session, m *discordgo.MessageCreate, st []string) error {
+		return ctx.controlPlayback(s, m, (*player.Player).Resume, "Resumed", "Nothing is paused")
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/tweekes0/pal-bot/internal/player"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Bot will show the guild's playback policy
+func (ctx *Context) showPolicy(s *discordgo.Session, m *discordgo.MessageCreate) error {
+	settings, err := ctx.settingsModel.Get(m.GuildID)
+	if err != nil {
+		return err
+	}
+
+	_, _ = s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("The playback policy is **%v**", settings.PlaybackPolicy))
+	return nil
+}
+
+// Bot will set what happens to soundbites played while another is playing in the guild
+func (ctx *Context) setPolicy(s *discordgo.Session, m *discordgo.MessageCreate, policy player.Policy) error {
+	settings, err := ctx.settingsModel.Get(m.GuildID)
+	if err != nil {
+		return err
+	}
+
+	settings.PlaybackPolicy = string(policy)
+	if err := ctx.settingsModel.Save(settings); err != nil {
+		return err
+	}
+
+	_, _ = s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("The playback policy is now **%v**", policy))
+	return nil
+}
+
+// Wrapper function for the 'policy' command
+func (ctx *Context) policyCommand() func(*discordgo.Session, *discordgo.MessageCreate, []string) error {
+	return func(s *discordgo.Session, m *discordgo.MessageCreate, st []string) error {
+		if len(st) < 1 {
+			return ctx.showPolicy(s, m)
+		}
+
+		policy, ok := player.ParsePolicy(st[0])
+		if !ok {
+			ctx.help(s, m, "policy")
+			return ErrInvalidSubcommand
+		}
+
+		return ctx.setPolicy(s, m, policy)
+	}
+}
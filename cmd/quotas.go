@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/tweekes0/pal-bot/config"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Gets the quota of the author of a message. Members with override roles get
+// the most generous of their overrides instead of the default quota.
+func (ctx *Context) quotaFor(m *discordgo.MessageCreate) config.QuotaLimit {
+	quota := ctx.botCfg.Quota
+	if m.Member == nil {
+		return quota.QuotaLimit
+	}
+
+	var limit config.QuotaLimit
+	overridden := false
+
+	for _, role := range m.Member.Roles {
+		override, ok := quota.Roles[role]
+		if !ok {
+			continue
+		}
+
+		if !overridden {
+			limit = override
+			overridden = true
+			continue
+		}
+
+		limit.MaxSounds = moreGenerous(limit.MaxSounds, override.MaxSounds)
+		limit.MaxSeconds = moreGenerous(limit.MaxSeconds, override.MaxSeconds)
+	}
+
+	if !overridden {
+		return quota.QuotaLimit
+	}
+
+	return limit
+}
+
+// Returns the larger of two limits where zero is unlimited
+func moreGenerous(a, b int) int {
+	if a == 0 || b == 0 {
+		return 0
+	}
+
+	if a > b {
+		return a
+	}
+
+	return b
+}
+
+// Checks that the author of a message can add the given number of soundbites and seconds
+// of audio to their library and lets them know if it would put them over their quota
+func (ctx *Context) checkQuota(s *discordgo.Session, m *discordgo.MessageCreate, sounds int, duration time.Duration) error {
+	count, total, err := ctx.soundbiteModel.Usage(m.Author.ID)
+	if err != nil {
+		return err
+	}
+
+	limit := ctx.quotaFor(m)
+	var msg string
+
+	switch {
+	case sounds > 0 && limit.MaxSounds > 0 && count+sounds > limit.MaxSounds:
+		msg = fmt.Sprintf("Sorry <@%v>, you have reached your limit of %v soundbites", m.Author.ID, limit.MaxSounds)
+	case limit.MaxSeconds > 0 && total+duration > time.Duration(limit.MaxSeconds)*time.Second:
+		msg = fmt.Sprintf("Sorry <@%v>, that would put you over your limit of %v seconds of audio, you have used %.0f",
+			m.Author.ID, limit.MaxSeconds, total.Seconds())
+	default:
+		return nil
+	}
+
+	_, _ = s.ChannelMessageSend(m.ChannelID, msg)
+	return ErrQuotaExceeded
+}
+
+// Formats a quota limit, zero is unlimited
+func formatLimit(limit int) string {
+	if limit == 0 {
+		return "unlimited"
+	}
+
+	return fmt.Sprint(limit)
+}
+
+// Bot will show how much of their quota the author of a message has used
+func (ctx *Context) showQuota(s *discordgo.Session, m *discordgo.MessageCreate) error {
+	count, total, err := ctx.soundbiteModel.Usage(m.Author.ID)
+	if err != nil {
+		return err
+	}
+
+	limit := ctx.quotaFor(m)
+	msg := fmt.Sprintf("<@%v> you have used **%v/%v** soundbites and **%.0f/%v** seconds of audio",
+		m.Author.ID, count, formatLimit(limit.MaxSounds), total.Seconds(), formatLimit(limit.MaxSeconds))
+
+	_, _ = s.ChannelMessageSend(m.ChannelID, msg)
+	return nil
+}
+
+// Wrapper function for the 'quota' command
+func (ctx *Context) quotaCommand() func(*discordgo.Session, *discordgo.MessageCreate, []string) error {
+	return func(s *discordgo.Session, m *discordgo.MessageCreate, st []string) error {
+		return ctx.showQuota(s, m)
+	}
+}
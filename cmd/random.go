@@ -0,0 +1,66 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/tweekes0/pal-bot/internal/models"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Args of the 'random' command that change how play counts weight the pick
+var weightings = map[string]models.Weighting{
+	"popular": models.WeightMostPlayed,
+	"rare":    models.WeightLeastPlayed,
+}
+
+// Parses the args of the 'random' command, a mention filters by creator, a
+// weighting name sets the weighting and any other arg is the tag to filter by
+func parseRandomArgs(m *discordgo.MessageCreate, args []string) models.RandomOptions {
+	opts := models.RandomOptions{}
+	if len(m.Mentions) > 0 {
+		opts.UserID = m.Mentions[0].ID
+	}
+
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "<@") {
+			continue
+		}
+
+		if w, ok := weightings[strings.ToLower(arg)]; ok {
+			opts.Weight = w
+			continue
+		}
+
+		opts.Tag = normalizeTag(arg)
+	}
+
+	return opts
+}
+
+// Bot will play a random soundbite matching the options
+func (ctx *Context) playRandom(s *discordgo.Session, m *discordgo.MessageCreate, opts models.RandomOptions) error {
+	name, err := ctx.soundbiteModel.Random(opts, nil)
+	if errors.Is(err, models.ErrNoRecords) {
+		_, _ = s.ChannelMessageSend(m.ChannelID, "there are no sounds to pick from :(")
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	msg := fmt.Sprintf("Playing **%v%v**", ctx.botCfg.CommandPrefix, name)
+	_, _ = s.ChannelMessageSend(m.ChannelID, msg)
+
+	return ctx.playSound(s, m, name)
+}
+
+// Wrapper function for the 'random' command
+func (ctx *Context) randomCommand() func(*discordgo.Session, *discordgo.MessageCreate, []string) error {
+	return func(s *discordgo.Session, m *discordgo.MessageCreate, st []string) error {
+		return ctx.playRandom(s, m, parseRandomArgs(m, st))
+	}
+}
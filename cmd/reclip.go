@@ -0,0 +1,124 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/tweekes0/pal-bot/config"
+	"github.com/tweekes0/pal-bot/internal/models"
+	"github.com/tweekes0/pal-bot/internal/sounds"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Bot will recreate a soundbite's audio from its source video with a new start time and
+// duration. The soundbite keeps its name, tags and plays, the old file is only removed
+// once the new one has replaced it.
+func (ctx *Context) reclip(s *discordgo.Session, m *discordgo.MessageCreate, name, startTime string, duration int) error {
+	sound, err := ctx.soundbiteModel.Get(name)
+	if err != nil {
+		return err
+	}
+
+	if err := ctx.authorizeSoundbite(s, m, sound); err != nil {
+		return err
+	}
+
+	if sound.SourceURL == "" {
+		msg := fmt.Sprintf("Sorry <@%v>, **%v** was not clipped from a video so it can't be reclipped", m.Author.ID, sound.Name)
+		_, _ = s.ChannelMessageSend(m.ChannelID, msg)
+		return ErrNoSource
+	}
+
+	// keep the current length when only the start time changes
+	if duration == 0 {
+		duration = int(math.Ceil(sound.Duration.Seconds()))
+	}
+
+	start, dur := getRuntime(startTime, duration)
+
+	// the quota belongs to the creator, mods fixing someone else's soundbite are not limited by it
+	if sound.UserID == m.Author.ID {
+		if err := ctx.checkQuota(s, m, 0, time.Duration(dur)*time.Second-sound.Duration); err != nil {
+			return err
+		}
+	}
+
+	f, mp3, _, err := sounds.CreateDCAFile(config.AUDIO_DIR, sound.SourceURL, start, dur)
+	if err != nil {
+		return err
+	}
+	defer sounds.DeleteFile(mp3.Name())
+
+	hash, err := sounds.HashFile(f.Name())
+	if err != nil {
+		sounds.DeleteFile(f.Name())
+		return err
+	}
+
+	length, err := sounds.DCADuration(f.Name())
+	if err != nil {
+		sounds.DeleteFile(f.Name())
+		return err
+	}
+
+	offset, err := sounds.StringToDuration(start)
+	if err != nil {
+		sounds.DeleteFile(f.Name())
+		return err
+	}
+
+	err = ctx.soundbiteModel.UpdateAudio(sound.Name, f.Name(), hash, length, offset)
+	if err != nil {
+		sounds.DeleteFile(f.Name())
+		return err
+	}
+
+	ctx.soundbiteCache.remove(sound.Name)
+
+	e := auditEntry(m, models.AuditReclip, sound)
+	e.OldValue = fmt.Sprintf("%v for %v", sound.Start, sound.Duration)
+	e.NewValue = fmt.Sprintf("%v for %v", offset, length)
+	ctx.record(s, e)
+
+	if err := deleteSoundFile(sound.FilePath); err != nil {
+		ctx.errorLogger.Println(err)
+	}
+
+	ms := &discordgo.MessageSend{
+		Content: fmt.Sprintf("**%v** has been reclipped. Play it with **%v%v**", sound.Name, ctx.botCfg.CommandPrefix, sound.Name),
+		Files:   []*discordgo.File{createDiscordFile(sound.Name, mp3)},
+	}
+
+	_, _ = s.ChannelMessageSendComplex(m.ChannelID, ms)
+	return nil
+}
+
+// Wrapper function for the 'reclip' command
+func (ctx *Context) reclipCommand() func(*discordgo.Session, *discordgo.MessageCreate, []string) error {
+	return func(s *discordgo.Session, m *discordgo.MessageCreate, st []string) error {
+		if len(st) < 2 {
+			ctx.help(s, m, "reclip")
+			return ErrNotEnoughArgs
+		}
+
+		duration := 0
+		if len(st) > 2 {
+			var err error
+			if duration, err = strconv.Atoi(st[2]); err != nil {
+				ctx.help(s, m, "reclip")
+				return err
+			}
+		}
+
+		err := ctx.reclip(s, m, st[0], st[1], duration)
+		if errors.Is(err, sounds.ErrInvalidStartTime) || errors.Is(err, sounds.ErrInvalidDuration) {
+			ctx.help(s, m, "reclip")
+		}
+
+		return err
+	}
+}
@@ -0,0 +1,264 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tweekes0/pal-bot/internal/models"
+	"github.com/tweekes0/pal-bot/internal/scheduler"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	MAX_USER_SCHEDULES    = 5               // The maximum number of schedules a user can have
+	MISSED_SCHEDULE_GRACE = 5 * time.Minute // How late a schedule missed while the bot was offline can still play
+)
+
+// Bot will add every saved schedule to the scheduler. One-off schedules missed by more than
+// MISSED_SCHEDULE_GRACE while the bot was offline are deleted and recurring ones skip ahead.
+func (ctx *Context) loadSchedules() error {
+	schedules, err := ctx.scheduleModel.List("")
+	if err != nil {
+		return err
+	}
+
+	now := ctx.clock.Now()
+	for _, sc := range schedules {
+		if now.Sub(sc.NextRun) > MISSED_SCHEDULE_GRACE {
+			if err := ctx.reschedule(sc, now); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		ctx.scheduler.Add(sc.ID, sc.NextRun)
+	}
+
+	return nil
+}
+
+// Bot will schedule the next run of a recurring schedule after now, a one-off schedule is deleted
+func (ctx *Context) reschedule(sc *models.Schedule, now time.Time) error {
+	if sc.Recurrence == "" {
+		return ctx.scheduleModel.Delete(sc.ID)
+	}
+
+	r, err := scheduler.ParseRecurrence(sc.Recurrence)
+	if err != nil {
+		return err
+	}
+
+	next := r.Next(now)
+	if err := ctx.scheduleModel.UpdateNextRun(sc.ID, next); err != nil {
+		return err
+	}
+
+	ctx.scheduler.Add(sc.ID, next)
+	return nil
+}
+
+// Bot will play a schedule's soundbite in its VoiceChannel, called by the scheduler when it is due
+func (ctx *Context) runSchedule(s *discordgo.Session, id int) {
+	sc, err := ctx.scheduleModel.Get(id)
+	if errors.Is(err, models.ErrDoesNotExist) {
+		return
+	}
+
+	if err != nil {
+		ctx.errorLogger.Println(err)
+		return
+	}
+
+	// the next run is saved first so a long soundbite can't delay it
+	if err := ctx.reschedule(sc, ctx.clock.Now()); err != nil {
+		ctx.errorLogger.Println(err)
+	}
+
+	// a soundbite in the trash plays again once it is restored
+	sound, err := ctx.getSoundbite(sc.SoundName)
+	if errors.Is(err, models.ErrDoesNotExist) {
+		return
+	}
+
+	if err != nil {
+		ctx.errorLogger.Println(err)
+		return
+	}
+
+	if err := ctx.streamToChannel(s, sc.GuildID, sc.ChannelID, sc.UserID, sound); err != nil {
+		ctx.errorLogger.Println(err)
+	}
+}
+
+// Formats when a schedule plays next and how often, as a Discord timestamp
+func formatSchedule(sc *models.Schedule) string {
+	when := fmt.Sprintf("<t:%v:f>", sc.NextRun.Unix())
+	if sc.Recurrence != "" {
+		when = fmt.Sprintf("%v, %v", when, sc.Recurrence)
+	}
+
+	return when
+}
+
+// Bot will schedule a soundbite to play in the given or the author's VoiceChannel
+func (ctx *Context) scheduleSound(s *discordgo.Session, m *discordgo.MessageCreate, name string, args []string) error {
+	channelID, ok := parseChannelMention(args[len(args)-1])
+	if ok {
+		args = args[:len(args)-1]
+		if err := ctx.authorizeChannel(s, m, channelID); err != nil {
+			return err
+		}
+	} else {
+		channelID = getChannelID(s, m)
+	}
+
+	if channelID == "" {
+		msg := fmt.Sprintf("<@%v> join a VoiceChannel or mention one to schedule a soundbite", m.Author.ID)
+		_, _ = s.ChannelMessageSend(m.ChannelID, msg)
+		return ErrUserNotInVC
+	}
+
+	if len(args) == 0 {
+		ctx.help(s, m, "schedule")
+		return ErrNotEnoughArgs
+	}
+
+	next, r, err := scheduler.Parse(strings.Join(args, " "), ctx.clock.Now())
+	if err != nil {
+		ctx.help(s, m, "schedule")
+		return err
+	}
+
+	count, err := ctx.scheduleModel.Count(m.Author.ID)
+	if err != nil {
+		return err
+	}
+
+	if count >= MAX_USER_SCHEDULES {
+		msg := fmt.Sprintf("Sorry <@%v>, you can only have %v schedules", m.Author.ID, MAX_USER_SCHEDULES)
+		_, _ = s.ChannelMessageSend(m.ChannelID, msg)
+		return ErrTooManySchedules
+	}
+
+	sc := &models.Schedule{
+		SoundName: name,
+		GuildID:   m.GuildID,
+		ChannelID: channelID,
+		UserID:    m.Author.ID,
+		Username:  m.Author.Username,
+		NextRun:   next,
+	}
+
+	if r != nil {
+		sc.Recurrence = r.String()
+	}
+
+	id, err := ctx.scheduleModel.Insert(sc)
+	if err != nil {
+		return err
+	}
+
+	ctx.scheduler.Add(id, next)
+
+	msg := fmt.Sprintf("**%v%v** will play in <#%v> %v, cancel it with **%vunschedule %v**",
+		ctx.botCfg.CommandPrefix, name, channelID, formatSchedule(sc), ctx.botCfg.CommandPrefix, id)
+	_, _ = s.ChannelMessageSend(m.ChannelID, msg)
+	return nil
+}
+
+// Bot will list the schedules of the message's guild in the order they play
+func (ctx *Context) listSchedules(s *discordgo.Session, m *discordgo.MessageCreate) error {
+	schedules, err := ctx.scheduleModel.List(m.GuildID)
+	if err != nil {
+		return err
+	}
+
+	if len(schedules) == 0 {
+		_, _ = s.ChannelMessageSend(m.ChannelID, "there are no schedules :(")
+		return nil
+	}
+
+	var b strings.Builder
+	fmt.Fprint(&b, "**Schedules:** \n")
+	for _, sc := range schedules {
+		fmt.Fprintf(&b, "%v. **%v%v** in <#%v> %v by %v\n",
+			sc.ID, ctx.botCfg.CommandPrefix, sc.SoundName, sc.ChannelID, formatSchedule(sc), sc.Username)
+	}
+
+	_, _ = s.ChannelMessageSend(m.ChannelID, b.String())
+	return nil
+}
+
+// Bot will cancel a schedule the author created
+func (ctx *Context) unscheduleSound(s *discordgo.Session, m *discordgo.MessageCreate, arg string) error {
+	id, err := strconv.Atoi(strings.TrimPrefix(arg, "#"))
+	if err != nil {
+		ctx.help(s, m, "unschedule")
+		return ErrInvalidSchedule
+	}
+
+	sc, err := ctx.scheduleModel.Get(id)
+	if err != nil {
+		return err
+	}
+
+	// schedules of other guilds can't be seen from this one
+	if sc.GuildID != m.GuildID {
+		return models.ErrDoesNotExist
+	}
+
+	name := fmt.Sprintf("schedule %v", sc.ID)
+	if err := ctx.authorizeOwner(s, m, name, sc.UserID, sc.Username); err != nil {
+		return err
+	}
+
+	if err := ctx.scheduleModel.Delete(sc.ID); err != nil {
+		return err
+	}
+
+	ctx.scheduler.Remove(sc.ID)
+
+	_, _ = s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("%v has been cancelled", name))
+	return nil
+}
+
+// Wrapper function for the 'schedule' command
+func (ctx *Context) scheduleCommand() func(*discordgo.Session, *discordgo.MessageCreate, []string) error {
+	return func(s *discordgo.Session, m *discordgo.MessageCreate, st []string) error {
+		if len(st) < 2 {
+			ctx.help(s, m, "schedule")
+			return ErrNotEnoughArgs
+		}
+
+		name, err := ctx.resolveAlias(st[0])
+		if err != nil {
+			return err
+		}
+
+		return ctx.scheduleSound(s, m, name, st[1:])
+	}
+}
+
+// Wrapper function for the 'schedules' command
+func (ctx *Context) schedulesCommand() func(*discordgo.Session, *discordgo.MessageCreate, []string) error {
+	return func(s *discordgo.Session, m *discordgo.MessageCreate, st []string) error {
+		return ctx.listSchedules(s, m)
+	}
+}
+
+// Wrapper function for the 'unschedule' command
+func (ctx *Context) unscheduleCommand() func(*discordgo.Session, *discordgo.MessageCreate, []string) error {
+	return func(s *discordgo.Session, m *discordgo.MessageCreate, st []string) error {
+		if len(st) < 1 {
+			ctx.help(s, m, "unschedule")
+			return ErrNotEnoughArgs
+		}
+
+		return ctx.unscheduleSound(s, m, st[0])
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tweekes0/pal-bot/internal/models"
+	"github.com/tweekes0/pal-bot/internal/scheduler"
+	test "github.com/tweekes0/pal-bot/internal/testing"
+)
+
+func TestLoadSchedules(t *testing.T) {
+	ctx, teardown := stateTestSetup(t)
+	defer teardown()
+
+	ctx.scheduleModel = &models.ScheduleModel{DB: ctx.soundbiteModel.DB}
+	if err := ctx.scheduleModel.Initialize(); err != nil {
+		t.Fatalf("failed to initialize schedules: %v", err)
+	}
+
+	ctx.scheduler = scheduler.New(ctx.clock, func(int) {})
+	defer ctx.scheduler.Stop()
+
+	now := ctx.clock.Now()
+	schedules := []struct {
+		name       string
+		nextRun    time.Time
+		recurrence string
+		want       time.Time
+		wantErr    error
+	}{
+		{"missed", now.Add(-time.Hour), "", time.Time{}, models.ErrDoesNotExist},
+		{"missed recurring", now.Add(-time.Hour), "every day 09:00", now.Add(9 * time.Hour), nil},
+		{"late", now.Add(-time.Minute), "", now.Add(-time.Minute), nil},
+		{"upcoming", now.Add(time.Hour), "", now.Add(time.Hour), nil},
+	}
+
+	ids := make([]int, len(schedules))
+	for i, sc := range schedules {
+		id, err := ctx.scheduleModel.Insert(&models.Schedule{
+			SoundName:  "sound0",
+			GuildID:    "guild",
+			ChannelID:  "channel",
+			UserID:     "111111",
+			Username:   "username",
+			Recurrence: sc.recurrence,
+			NextRun:    sc.nextRun,
+		})
+		if err != nil {
+			t.Fatalf("failed to insert schedule: %v", err)
+		}
+
+		ids[i] = id
+	}
+
+	if err := ctx.loadSchedules(); err != nil {
+		t.Fatalf("failed to load schedules: %v", err)
+	}
+
+	if ctx.scheduler.Len() != 3 {
+		t.Errorf("expected 3 scheduled jobs, got %v", ctx.scheduler.Len())
+	}
+
+	for i, tc := range schedules {
+		sc, err := ctx.scheduleModel.Get(ids[i])
+		test.AssertError(t, err, tc.wantErr)
+
+		if err == nil && !sc.NextRun.Equal(tc.want) {
+			t.Errorf("%v: expected next run at %v, got %v", tc.name, tc.want, sc.NextRun)
+		}
+	}
+}
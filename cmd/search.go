@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	MAX_SEARCH_RESULTS = 10 // The maximum number of soundbites shown by the 'search' command
+)
+
+// Bot will show the soundbites that best match the query
+func (ctx *Context) search(s *discordgo.Session, m *discordgo.MessageCreate, query string) error {
+	sounds, err := ctx.searchModel.Search(query, MAX_SEARCH_RESULTS)
+	if err != nil {
+		return err
+	}
+
+	if len(sounds) == 0 {
+		msg := fmt.Sprintf("no sounds matched **%v** :(", query)
+		_, _ = s.ChannelMessageSend(m.ChannelID, msg)
+		return nil
+	}
+
+	var b strings.Builder
+	for _, sound := range sounds {
+		fmt.Fprintf(&b, "**%v%v** · %v", ctx.botCfg.CommandPrefix, sound.Name, sound.Username)
+
+		tags, err := ctx.tagModel.Get(sound.Name)
+		if err == nil && len(tags) > 0 {
+			fmt.Fprintf(&b, " · %v", strings.Join(tags, ", "))
+		}
+
+		fmt.Fprint(&b, "\n")
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("Sounds matching %v", query),
+		Description: b.String(),
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("Play one with %v%v", ctx.botCfg.CommandPrefix, sounds[0].Name),
+		},
+	}
+
+	_, err = s.ChannelMessageSendEmbed(m.ChannelID, embed)
+	return err
+}
+
+// Wrapper function for the 'search' command
+func (ctx *Context) searchCommand() func(*discordgo.Session, *discordgo.MessageCreate, []string) error {
+	return func(s *discordgo.Session, m *discordgo.MessageCreate, st []string) error {
+		if len(st) < 1 {
+			ctx.help(s, m, "search")
+			return ErrNotEnoughArgs
+		}
+
+		return ctx.search(s, m, strings.Join(st, " "))
+	}
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"sync"
+
+	"github.com/tweekes0/pal-bot/internal/models"
+	"github.com/tweekes0/pal-bot/internal/player"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Cache of soundbites by name. discordgo runs handlers concurrently
+// so the cache is shared by every command.
+type soundCache struct {
+	mu     sync.RWMutex
+	sounds map[string]*models.Soundbite
+}
+
+// Creates an empty soundCache
+func newSoundCache() *soundCache {
+	return &soundCache{sounds: map[string]*models.Soundbite{}}
+}
+
+// Gets a soundbite from the cache
+func (c *soundCache) get(name string) (*models.Soundbite, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	s, ok := c.sounds[name]
+	return s, ok
+}
+
+// Adds a soundbite to the cache, replacing any with the same name
+func (c *soundCache) set(name string, s *models.Soundbite) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.sounds[name] = s
+}
+
+// Removes soundbites from the cache so they are loaded from the db next time
+func (c *soundCache) remove(names ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, name := range names {
+		delete(c.sounds, name)
+	}
+}
+
+// Struct for the player of a voice connection along with what watches it
+type playback struct {
+	player     *player.Player
+	supervisor *player.Supervisor
+	idle       *player.IdleTimer
+}
+
+// Stops the player, its supervisor and its idle timer, sounds that are
+// playing or waiting to play are dropped
+func (p *playback) close() {
+	p.supervisor.Stop()
+	p.player.Close()
+	p.idle.Stop()
+}
+
+// The bot's voice connection and what plays on it. Handlers, timers and the player's
+// supervisor all change it from their own goroutines so it is only used with the lock held.
+type voiceState struct {
+	joinMu   sync.Mutex // Held for a whole join so joins don't race each other
+	mu       sync.Mutex // Guards the fields below, never held while talking to discord
+	vc       *discordgo.VoiceConnection
+	joined   bool
+	playback *playback
+}
+
+// Joins a voice channel, one join at a time. The connection is made without holding the
+// state lock so playback and voice state updates are not held up by it. A new connection
+// gets new playback from start and the old playback is closed.
+func (v *voiceState) join(join func() (*discordgo.VoiceConnection, error), start func(*discordgo.VoiceConnection) *playback) (*playback, error) {
+	v.joinMu.Lock()
+	defer v.joinMu.Unlock()
+
+	vc, err := join()
+	if err != nil {
+		return nil, err
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.playback == nil || vc != v.vc {
+		v.closePlayback()
+		v.playback = start(vc)
+	}
+	v.vc = vc
+	v.joined = true
+
+	return v.playback, nil
+}
+
+// Replaces a voice connection that was rejoined, unless the bot has moved on from it since
+func (v *voiceState) rejoined(old, vc *discordgo.VoiceConnection) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.vc == old {
+		v.vc = vc
+	}
+}
+
+// Closes the playback of the voice connection and forgets it. Returns the connection
+// to disconnect, or nil if the bot is not in voice or has moved to another connection.
+func (v *voiceState) leave(vc *discordgo.VoiceConnection) *discordgo.VoiceConnection {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if !v.joined || (vc != nil && vc != v.vc) {
+		return nil
+	}
+
+	v.closePlayback()
+	v.joined = false
+
+	return v.vc
+}
+
+// Sets whether the bot is in voice, leaving voice closes its playback
+func (v *voiceState) setJoined(joined bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.joined = joined
+	if !joined {
+		v.closePlayback()
+	}
+}
+
+// Gets the voice connection and whether the bot is in voice
+func (v *voiceState) connection() (*discordgo.VoiceConnection, bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	return v.vc, v.joined
+}
+
+// Gets the playback of the voice connection, nil if there is none
+func (v *voiceState) current() *playback {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	return v.playback
+}
+
+// Closes the playback, the caller must hold the lock
+func (v *voiceState) closePlayback() {
+	if v.playback != nil {
+		v.playback.close()
+		v.playback = nil
+	}
+}
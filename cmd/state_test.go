@@ -0,0 +1,390 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tweekes0/pal-bot/config"
+	"github.com/tweekes0/pal-bot/internal/clock"
+	"github.com/tweekes0/pal-bot/internal/models"
+	"github.com/tweekes0/pal-bot/internal/player"
+	test "github.com/tweekes0/pal-bot/internal/testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const SIMULATED_MESSAGES = 50 // The number of messages handled at the same time in each test
+
+// Creates a Context with a soundbite db holding 'sound0' to 'sound4'
+func stateTestSetup(t *testing.T) (*Context, func()) {
+	t.Parallel()
+
+	f, err := ioutil.TempFile("", "*")
+	if err != nil {
+		t.Fatalf("failed to create file: %v", err)
+	}
+
+	db, err := openDB(f.Name())
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+
+	ctx := &Context{
+		soundbiteModel: &models.SoundbiteModel{DB: db},
+		soundbiteCache: newSoundCache(),
+		clock:          clock.NewFake(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)),
+	}
+	ctx.soundbiteModel.Initialize()
+
+	for i := 0; i < 5; i++ {
+		_, err := ctx.soundbiteModel.Insert(&models.Soundbite{
+			Name:     fmt.Sprint("sound", i),
+			Username: "username",
+			UserID:   "111111",
+			FilePath: fmt.Sprint("/path/to/file/", i),
+			FileHash: fmt.Sprint("sha256:", i),
+		})
+		if err != nil {
+			t.Fatalf("failed to insert soundbite: %v", err)
+		}
+	}
+
+	teardown := func() {
+		db.Close()
+		os.Remove(f.Name())
+	}
+
+	return ctx, teardown
+}
+
+// Creates a Context that handles messages from the 'channel' channel with every command.
+// Each user can play one soundbite an hour.
+func handlerTestSetup(t *testing.T) (*Context, func()) {
+	ctx, teardown := stateTestSetup(t)
+
+	ctx.botID = "bot"
+	ctx.botCfg = &config.BotConfig{CommandPrefix: "!", BotChannelID: "channel"}
+	ctx.errorLogger = log.New(ioutil.Discard, "", 0)
+	ctx.infoLogger = log.New(ioutil.Discard, "", 0)
+	ctx.playbackLimiter = newLimiter(config.LimitConfig{Burst: 1, Interval: 3600})
+	ctx.creationLimiter = newLimiter(config.LimitConfig{Burst: 1, Interval: 3600})
+
+	ctx.aliasModel = &models.AliasModel{DB: ctx.soundbiteModel.DB}
+	if err := ctx.aliasModel.Initialize(); err != nil {
+		t.Fatalf("failed to initialize aliases: %v", err)
+	}
+
+	ctx.playlistModel = &models.PlaylistModel{DB: ctx.soundbiteModel.DB}
+	if err := ctx.playlistModel.Initialize(); err != nil {
+		t.Fatalf("failed to initialize playlists: %v", err)
+	}
+
+	ctx.settingsModel = &models.SettingsModel{DB: ctx.soundbiteModel.DB}
+	if err := ctx.settingsModel.Initialize(); err != nil {
+		t.Fatalf("failed to initialize settings: %v", err)
+	}
+
+	ctx.themeModel = &models.ThemeModel{DB: ctx.soundbiteModel.DB}
+	if err := ctx.themeModel.Initialize(); err != nil {
+		t.Fatalf("failed to initialize themes: %v", err)
+	}
+
+	ctx.commands = ctx.getCommands(ctx.botCfg.CommandPrefix)
+
+	return ctx, teardown
+}
+
+// Struct for a fake discord API that records the messages the bot sends
+type fakeDiscord struct {
+	mu       sync.Mutex
+	messages []string
+}
+
+// Records a sent message and answers every request with an empty object
+func (d *fakeDiscord) RoundTrip(r *http.Request) (*http.Response, error) {
+	if r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/messages") {
+		var msg discordgo.MessageSend
+		if err := json.NewDecoder(r.Body).Decode(&msg); err == nil {
+			d.mu.Lock()
+			d.messages = append(d.messages, msg.Content)
+			d.mu.Unlock()
+		}
+	}
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       ioutil.NopCloser(strings.NewReader("{}")),
+		Request:    r,
+	}, nil
+}
+
+// Gets the messages sent so far
+func (d *fakeDiscord) sent() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return append([]string{}, d.messages...)
+}
+
+// Creates a session that talks to a fake discord API instead of the real one
+func fakeSession(t *testing.T) (*discordgo.Session, *fakeDiscord) {
+	s, err := discordgo.New("Bot token")
+	if err != nil {
+		t.Fatalf("failed to create session: %v", err)
+	}
+
+	d := &fakeDiscord{}
+	s.Client = &http.Client{Transport: d}
+
+	return s, d
+}
+
+// Creates a message sent by a user in the bot's channel
+func fakeMessage(uid, content string) *discordgo.MessageCreate {
+	return &discordgo.MessageCreate{Message: &discordgo.Message{
+		Content:   content,
+		ChannelID: "channel",
+		GuildID:   "guild",
+		Author:    &discordgo.User{ID: uid, Username: fmt.Sprint("user", uid)},
+	}}
+}
+
+// Runs a function as if it was handling that many messages at the same time
+func simulateMessages(n int, handle func(i int)) {
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			handle(i)
+		}(i)
+	}
+
+	wg.Wait()
+}
+
+// Creates playback for a fake voice connection that nothing reads from
+func fakePlayback(c clock.Clock, vc *discordgo.VoiceConnection) *playback {
+	p := player.New(player.Output{Send: vc.OpusSend, Speaking: func(bool) error { return nil }})
+	rejoin := func() (player.Output, error) { return player.Output{}, ErrBotNotInVC }
+
+	return &playback{
+		player:     p,
+		supervisor: player.NewSupervisor(c, p, voiceSupervision, rejoin, func(error) {}),
+		idle:       player.NewIdleTimer(c, time.Minute, func() {}),
+	}
+}
+
+func TestSoundCache(t *testing.T) {
+	t.Run("get, rename and delete soundbites at the same time", func(t *testing.T) {
+		ctx, teardown := stateTestSetup(t)
+		defer teardown()
+
+		simulateMessages(SIMULATED_MESSAGES, func(i int) {
+			name := fmt.Sprint("sound", i%5)
+
+			switch i % 3 {
+			case 0:
+				s, err := ctx.getSoundbite(name)
+				test.AssertError(t, err, nil)
+				test.AssertType(t, s.Name, name)
+			case 1:
+				ctx.soundbiteCache.remove(name)
+			case 2:
+				ctx.soundbiteCache.set(name, &models.Soundbite{Name: name})
+			}
+		})
+
+		for i := 0; i < 5; i++ {
+			name := fmt.Sprint("sound", i)
+			s, err := ctx.getSoundbite(name)
+			test.AssertError(t, err, nil)
+			test.AssertType(t, s.Name, name)
+		}
+	})
+}
+
+func TestVoiceState(t *testing.T) {
+	t.Run("join, play, control and leave at the same time", func(t *testing.T) {
+		ctx, teardown := stateTestSetup(t)
+		defer teardown()
+
+		connections := []*discordgo.VoiceConnection{
+			{OpusSend: make(chan []byte)},
+			{OpusSend: make(chan []byte)},
+		}
+
+		simulateMessages(SIMULATED_MESSAGES, func(i int) {
+			vc := connections[i%len(connections)]
+
+			switch i % 5 {
+			case 0, 1:
+				join := func() (*discordgo.VoiceConnection, error) { return vc, nil }
+				start := func(vc *discordgo.VoiceConnection) *playback { return fakePlayback(ctx.clock, vc) }
+
+				pb, err := ctx.voice.join(join, start)
+				test.AssertError(t, err, nil)
+				pb.player.Play([][]byte{{1}})
+			case 2:
+				if pb := ctx.voice.current(); pb != nil {
+					pb.player.Skip()
+				}
+			case 3:
+				ctx.voice.leave(nil)
+			case 4:
+				ctx.voice.setJoined(i%2 == 0)
+			}
+
+			ctx.voice.connection()
+		})
+
+		ctx.voice.setJoined(false)
+		test.AssertType(t, ctx.voice.current() == nil, true)
+	})
+
+	t.Run("new connection closes the old playback", func(t *testing.T) {
+		ctx, teardown := stateTestSetup(t)
+		defer teardown()
+
+		first := &discordgo.VoiceConnection{OpusSend: make(chan []byte)}
+		second := &discordgo.VoiceConnection{OpusSend: make(chan []byte)}
+		start := func(vc *discordgo.VoiceConnection) *playback { return fakePlayback(ctx.clock, vc) }
+
+		pb, err := ctx.voice.join(func() (*discordgo.VoiceConnection, error) { return first, nil }, start)
+		test.AssertError(t, err, nil)
+		done := pb.player.Play([][]byte{{1}})
+
+		same, err := ctx.voice.join(func() (*discordgo.VoiceConnection, error) { return first, nil }, start)
+		test.AssertError(t, err, nil)
+		test.AssertType(t, same == pb, true)
+
+		_, err = ctx.voice.join(func() (*discordgo.VoiceConnection, error) { return second, nil }, start)
+		test.AssertError(t, err, nil)
+		test.AssertError(t, <-done, player.ErrClosed)
+
+		// a connection the bot has moved on from is not left
+		test.AssertType(t, ctx.voice.leave(first) == nil, true)
+		test.AssertType(t, ctx.voice.leave(second), second)
+		test.AssertType(t, ctx.voice.leave(nil) == nil, true)
+	})
+
+	t.Run("joining does not hold up the voice state", func(t *testing.T) {
+		ctx, teardown := stateTestSetup(t)
+		defer teardown()
+
+		vc := &discordgo.VoiceConnection{OpusSend: make(chan []byte)}
+		joining, connected := make(chan struct{}), make(chan struct{})
+
+		join := func() (*discordgo.VoiceConnection, error) {
+			close(joining)
+			<-connected
+			return vc, nil
+		}
+		start := func(vc *discordgo.VoiceConnection) *playback { return fakePlayback(ctx.clock, vc) }
+
+		joined := make(chan *playback)
+		go func() {
+			pb, _ := ctx.voice.join(join, start)
+			joined <- pb
+		}()
+
+		<-joining
+		ctx.voice.setJoined(true)
+		test.AssertType(t, ctx.voice.current() == nil, true)
+
+		close(connected)
+		pb := <-joined
+		test.AssertType(t, ctx.voice.current(), pb)
+
+		got, ok := ctx.voice.connection()
+		test.AssertType(t, got, vc)
+		test.AssertType(t, ok, true)
+	})
+}
+
+func TestHandlers(t *testing.T) {
+	t.Run("handle messages and voice state updates at the same time", func(t *testing.T) {
+		ctx, teardown := handlerTestSetup(t)
+		defer teardown()
+
+		for i := 0; i < 5; i++ {
+			err := ctx.aliasModel.Insert(fmt.Sprint("s", i), fmt.Sprint("sound", i), "username", "111111")
+			if err != nil {
+				t.Fatalf("failed to insert alias: %v", err)
+			}
+		}
+
+		// members are never in voice, joining a channel needs a connection to discord
+		s, d := fakeSession(t)
+		err := s.State.GuildAdd(&discordgo.Guild{
+			ID:          "guild",
+			VoiceStates: []*discordgo.VoiceState{{GuildID: "guild", UserID: ctx.botID, ChannelID: "voice"}},
+		})
+		if err != nil {
+			t.Fatalf("failed to add guild: %v", err)
+		}
+
+		voiceUpdate := func(uid, channelID string) *discordgo.VoiceStateUpdate {
+			return &discordgo.VoiceStateUpdate{VoiceState: &discordgo.VoiceState{
+				GuildID:   "guild",
+				UserID:    uid,
+				ChannelID: channelID,
+			}}
+		}
+
+		combos := 0
+		for i := 0; i < SIMULATED_MESSAGES; i++ {
+			if i%6 == 3 {
+				combos++
+			}
+		}
+
+		simulateMessages(SIMULATED_MESSAGES, func(i int) {
+			uid := fmt.Sprint(1000 + i)
+			n := i % 5
+
+			switch i % 6 {
+			case 0:
+				ctx.messageCreate(s, fakeMessage(uid, fmt.Sprint("!sound", n)))
+			case 1:
+				ctx.messageCreate(s, fakeMessage(uid, fmt.Sprint("!s", n)))
+			case 2:
+				ctx.messageCreate(s, fakeMessage(uid, fmt.Sprint("!sonud", n)))
+			case 3:
+				ctx.messageCreate(s, fakeMessage(uid, "!combo list"))
+			case 4:
+				channelID := ""
+				if i%2 == 0 {
+					channelID = "voice"
+				}
+
+				ctx.voiceStateChange(s, voiceUpdate(ctx.botID, channelID))
+			case 5:
+				ctx.voiceStateChange(s, voiceUpdate(uid, "voice"))
+			}
+		})
+
+		replies := 0
+		for _, msg := range d.sent() {
+			if msg == "there are no combos :(" {
+				replies++
+			}
+		}
+		test.AssertType(t, replies, combos)
+
+		for i := 0; i < 5; i++ {
+			sound, ok := ctx.soundbiteCache.get(fmt.Sprint("sound", i))
+			test.AssertType(t, ok, true)
+			test.AssertType(t, sound.Name, fmt.Sprint("sound", i))
+		}
+	})
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tweekes0/pal-bot/internal/models"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	MAX_LEADERBOARD_ENTRIES = 10 // The maximum number of soundbites shown by the 'top' command
+	MAX_STATS_ENTRIES       = 5  // The maximum number of users or soundbites listed by 'stats' and 'mystats'
+)
+
+// Struct for a period of time the 'top' command can rank plays over
+type period struct {
+	name     string
+	label    string
+	duration time.Duration // Zero counts every play
+}
+
+// Periods in the order they are listed in the 'top' command's help
+var periods = []period{
+	{name: "day", label: "today", duration: 24 * time.Hour},
+	{name: "week", label: "this week", duration: 7 * 24 * time.Hour},
+	{name: "month", label: "this month", duration: 30 * 24 * time.Hour},
+	{name: "year", label: "this year", duration: 365 * 24 * time.Hour},
+	{name: "all", label: "of all time"},
+}
+
+// Finds a period by its name
+func findPeriod(name string) (period, bool) {
+	for _, p := range periods {
+		if p.name == strings.ToLower(name) {
+			return p, true
+		}
+	}
+
+	return period{}, false
+}
+
+// Gets the start of a period, a zero time for periods that count every play
+func (p period) since(now time.Time) time.Time {
+	if p.duration == 0 {
+		return time.Time{}
+	}
+
+	return now.Add(-p.duration)
+}
+
+// Formats play counts as a numbered list, format turns a name into how it is shown
+func formatPlayCounts(counts []*models.PlayCount, format func(string) string) string {
+	var b strings.Builder
+	for i, c := range counts {
+		fmt.Fprintf(&b, "%v. %v · %v plays\n", i+1, format(c.Name), c.Plays)
+	}
+
+	return b.String()
+}
+
+// Bot will show how often a soundbite has been played in the guild and who played it most
+func (ctx *Context) showStats(s *discordgo.Session, m *discordgo.MessageCreate, name string) error {
+	sound, err := ctx.soundbiteModel.Get(name)
+	if err != nil {
+		return err
+	}
+
+	stats, err := ctx.playModel.SoundbiteStats(sound.ID, m.GuildID, MAX_STATS_ENTRIES)
+	if err != nil {
+		return err
+	}
+
+	if stats.Plays == 0 {
+		msg := fmt.Sprintf("**%v** has not been played yet", sound.Name)
+		_, _ = s.ChannelMessageSend(m.ChannelID, msg)
+		return nil
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Played **%v** times by **%v** users\n", stats.Plays, stats.Players)
+	fmt.Fprintf(&b, "Last played <t:%v:R>\n\n", stats.LastPlayed.Unix())
+	fmt.Fprintf(&b, "**Top players:**\n")
+	fmt.Fprint(&b, formatPlayCounts(stats.TopPlayers, func(uid string) string {
+		return fmt.Sprintf("<@%v>", uid)
+	}))
+
+	embed := &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("Stats for %v%v", ctx.botCfg.CommandPrefix, sound.Name),
+		Description: b.String(),
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("Created by %v", sound.Username),
+		},
+	}
+
+	_, err = s.ChannelMessageSendEmbed(m.ChannelID, embed)
+	return err
+}
+
+// Bot will show the most played soundbites in the guild over a period
+func (ctx *Context) showTop(s *discordgo.Session, m *discordgo.MessageCreate, p period) error {
+	top, err := ctx.playModel.Top(m.GuildID, p.since(time.Now()), MAX_LEADERBOARD_ENTRIES)
+	if err != nil {
+		return err
+	}
+
+	if len(top) == 0 {
+		msg := fmt.Sprintf("no sounds have been played %v :(", p.label)
+		_, _ = s.ChannelMessageSend(m.ChannelID, msg)
+		return nil
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title: fmt.Sprintf("Top sounds %v", p.label),
+		Description: formatPlayCounts(top, func(name string) string {
+			return fmt.Sprintf("**%v%v**", ctx.botCfg.CommandPrefix, name)
+		}),
+	}
+
+	_, err = s.ChannelMessageSendEmbed(m.ChannelID, embed)
+	return err
+}
+
+// Bot will show how many soundbites the author of a message has played and their favorites
+func (ctx *Context) showUserStats(s *discordgo.Session, m *discordgo.MessageCreate) error {
+	stats, err := ctx.playModel.UserStats(m.Author.ID, m.GuildID, MAX_STATS_ENTRIES)
+	if err != nil {
+		return err
+	}
+
+	if stats.Plays == 0 {
+		msg := fmt.Sprintf("<@%v> you have not played any sounds yet", m.Author.ID)
+		_, _ = s.ChannelMessageSend(m.ChannelID, msg)
+		return nil
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "<@%v> you have played **%v** different sounds **%v** times\n\n", m.Author.ID, stats.Sounds, stats.Plays)
+	fmt.Fprintf(&b, "**Favorites:**\n")
+	fmt.Fprint(&b, formatPlayCounts(stats.Favorites, func(name string) string {
+		return fmt.Sprintf("**%v%v**", ctx.botCfg.CommandPrefix, name)
+	}))
+
+	embed := &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("Stats for %v", m.Author.Username),
+		Description: b.String(),
+	}
+
+	_, err = s.ChannelMessageSendEmbed(m.ChannelID, embed)
+	return err
+}
+
+// Wrapper function for the 'stats' command
+func (ctx *Context) statsCommand() func(*discordgo.Session, *discordgo.MessageCreate, []string) error {
+	return func(s *discordgo.Session, m *discordgo.MessageCreate, st []string) error {
+		if len(st) < 1 {
+			ctx.help(s, m, "stats")
+			return ErrNotEnoughArgs
+		}
+
+		return ctx.showStats(s, m, st[0])
+	}
+}
+
+// Wrapper function for the 'top' command
+func (ctx *Context) topCommand() func(*discordgo.Session, *discordgo.MessageCreate, []string) error {
+	return func(s *discordgo.Session, m *discordgo.MessageCreate, st []string) error {
+		p := periods[len(periods)-1]
+		if len(st) > 0 {
+			var ok bool
+			if p, ok = findPeriod(st[0]); !ok {
+				ctx.help(s, m, "top")
+				return ErrInvalidPeriod
+			}
+		}
+
+		return ctx.showTop(s, m, p)
+	}
+}
+
+// Wrapper function for the 'mystats' command
+func (ctx *Context) mystatsCommand() func(*discordgo.Session, *discordgo.MessageCreate, []string) error {
+	return func(s *discordgo.Session, m *discordgo.MessageCreate, st []string) error {
+		return ctx.showUserStats(s, m)
+	}
+}
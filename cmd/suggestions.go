@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tweekes0/pal-bot/internal/fuzzy"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	MAX_SUGGESTIONS       = 3 // The maximum number of commands and sounds suggested
+	AUTOPLAY_MAX_DISTANCE = 1 // How many typos away a sound can be and still be played automatically
+)
+
+// Bot will suggest the commands and sounds closest to an unknown one, or play the
+// suggested sound if auto play is enabled and it is the only very close match
+func (ctx *Context) suggest(s *discordgo.Session, m *discordgo.MessageCreate, name string) error {
+	sounds, err := ctx.soundbiteModel.Names()
+	if err != nil {
+		return err
+	}
+
+	isSound := make(map[string]bool)
+	for _, sound := range sounds {
+		isSound[sound] = true
+	}
+
+	candidates := sounds
+	for k := range ctx.commands {
+		candidates = append(candidates, strings.TrimPrefix(k, ctx.botCfg.CommandPrefix))
+	}
+
+	matches := fuzzy.Closest(name, candidates, fuzzy.MaxDistance(name), MAX_SUGGESTIONS)
+	if len(matches) == 0 {
+		return nil
+	}
+
+	if ctx.botCfg.Suggestions.AutoPlay && len(matches) == 1 &&
+		matches[0].Distance <= AUTOPLAY_MAX_DISTANCE && isSound[matches[0].Value] {
+		if !ctx.allow(s, m, ctx.playbackLimiter, "play") {
+			return nil
+		}
+
+		return ctx.playSound(s, m, matches[0].Value)
+	}
+
+	suggestions := []string{}
+	for _, match := range matches {
+		suggestions = append(suggestions, fmt.Sprintf("**%v%v**", ctx.botCfg.CommandPrefix, match.Value))
+	}
+
+	msg := fmt.Sprintf("<@%v> did you mean %v?", m.Author.ID, strings.Join(suggestions, " or "))
+	_, _ = s.ChannelMessageSend(m.ChannelID, msg)
+
+	return nil
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/tweekes0/pal-bot/internal/models"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	MAX_TAG_LENGTH = 32 // The maximum number of characters in a tag
+)
+
+// Lowercases a tag and strips a leading '#' so '#Memes' and 'memes' are the same tag
+func normalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimPrefix(tag, "#"))
+}
+
+// Normalizes a list of tags, returns ErrInvalidTag if any of them are empty, too long or
+// contain a ':', which separates the state stored in a 'sounds' listing's CustomIDs
+func parseTags(args []string) ([]string, error) {
+	tags := []string{}
+	for _, arg := range args {
+		tag := normalizeTag(arg)
+		if tag == "" || len(tag) > MAX_TAG_LENGTH || strings.Contains(tag, ":") {
+			return nil, ErrInvalidTag
+		}
+
+		tags = append(tags, tag)
+	}
+
+	return tags, nil
+}
+
+// Bot will add or remove tags from a soundbite the author created
+func (ctx *Context) updateTags(s *discordgo.Session, m *discordgo.MessageCreate, name string, tags []string, remove bool) error {
+	sound, err := ctx.soundbiteModel.Get(name)
+	if err != nil {
+		return err
+	}
+
+	if err := ctx.authorizeSoundbite(s, m, sound); err != nil {
+		return err
+	}
+
+	if remove {
+		err = ctx.tagModel.Remove(name, tags...)
+	} else {
+		err = ctx.tagModel.Add(name, tags...)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	action := models.AuditTag
+	if remove {
+		action = models.AuditUntag
+	}
+
+	e := auditEntry(m, action, sound)
+	e.NewValue = strings.Join(tags, ", ")
+	ctx.record(s, e)
+
+	return ctx.showTags(s, m, name)
+}
+
+// Bot will show the tags of a soundbite
+func (ctx *Context) showTags(s *discordgo.Session, m *discordgo.MessageCreate, name string) error {
+	tags, err := ctx.tagModel.Get(name)
+	if err != nil {
+		return err
+	}
+
+	msg := fmt.Sprintf("**%v** has no tags", name)
+	if len(tags) > 0 {
+		msg = fmt.Sprintf("**%v** is tagged %v", name, strings.Join(tags, ", "))
+	}
+
+	_, _ = s.ChannelMessageSend(m.ChannelID, msg)
+	return nil
+}
+
+// Bot will show every tag and how many soundbites have it
+func (ctx *Context) showTagCounts(s *discordgo.Session, m *discordgo.MessageCreate) error {
+	counts, err := ctx.tagModel.Counts()
+	if err != nil && !errors.Is(err, models.ErrNoRecords) {
+		return err
+	}
+
+	if len(counts) == 0 {
+		_, _ = s.ChannelMessageSend(m.ChannelID, "there are no tags :(")
+		return nil
+	}
+
+	var b strings.Builder
+	fmt.Fprint(&b, "**Tags:** \n")
+	for _, c := range counts {
+		fmt.Fprintf(&b, "%v (%v)\n", c.Tag, c.Count)
+	}
+
+	_, _ = s.ChannelMessageSend(m.ChannelID, b.String())
+	return nil
+}
+
+// Wrapper function for the 'tag' command
+func (ctx *Context) tagCommand() func(*discordgo.Session, *discordgo.MessageCreate, []string) error {
+	return func(s *discordgo.Session, m *discordgo.MessageCreate, st []string) error {
+		if len(st) < 2 {
+			ctx.help(s, m, "tag")
+			return ErrNotEnoughArgs
+		}
+
+		tags, err := parseTags(st[1:])
+		if err != nil {
+			ctx.help(s, m, "tag")
+			return err
+		}
+
+		return ctx.updateTags(s, m, st[0], tags, false)
+	}
+}
+
+// Wrapper function for the 'untag' command
+func (ctx *Context) untagCommand() func(*discordgo.Session, *discordgo.MessageCreate, []string) error {
+	return func(s *discordgo.Session, m *discordgo.MessageCreate, st []string) error {
+		if len(st) < 2 {
+			ctx.help(s, m, "untag")
+			return ErrNotEnoughArgs
+		}
+
+		tags, err := parseTags(st[1:])
+		if err != nil {
+			ctx.help(s, m, "untag")
+			return err
+		}
+
+		return ctx.updateTags(s, m, st[0], tags, true)
+	}
+}
+
+// Wrapper function for the 'tags' command
+func (ctx *Context) tagsCommand() func(*discordgo.Session, *discordgo.MessageCreate, []string) error {
+	return func(s *discordgo.Session, m *discordgo.MessageCreate, st []string) error {
+		if len(st) > 0 {
+			return ctx.showTags(s, m, st[0])
+		}
+
+		return ctx.showTagCounts(s, m)
+	}
+}
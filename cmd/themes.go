@@ -0,0 +1,187 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/tweekes0/pal-bot/internal/models"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Gets which theme a voice state update should play and in which channel. Joining or
+// moving to a channel plays the member's intro there, leaving plays their outro in
+// the channel they left.
+func themeEvent(vs *discordgo.VoiceStateUpdate) (models.ThemeKind, string, bool) {
+	before := ""
+	if vs.BeforeUpdate != nil {
+		before = vs.BeforeUpdate.ChannelID
+	}
+
+	switch {
+	case vs.ChannelID != "" && vs.ChannelID != before:
+		return models.ThemeIntro, vs.ChannelID, true
+	case vs.ChannelID == "" && before != "":
+		return models.ThemeOutro, before, true
+	}
+
+	return "", "", false
+}
+
+// Checks whether anyone other than the bot and the given user is in a voice channel
+func hasListeners(g *discordgo.Guild, channelID, uid, botID string) bool {
+	for _, vs := range g.VoiceStates {
+		if vs.ChannelID == channelID && vs.UserID != uid && vs.UserID != botID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Bot will play a member's intro or outro when they join or leave a voice channel. Themes
+// are skipped while the guild has them turned off, while the member is on cooldown, when
+// no one is left to hear them or when the bot is busy in another channel.
+func (ctx *Context) playTheme(s *discordgo.Session, vs *discordgo.VoiceStateUpdate) error {
+	if vs.UserID == ctx.botID {
+		return nil
+	}
+
+	kind, channelID, ok := themeEvent(vs)
+	if !ok {
+		return nil
+	}
+
+	settings, err := ctx.settingsModel.Get(vs.GuildID)
+	if err != nil {
+		return err
+	}
+
+	if !settings.ThemesEnabled {
+		return nil
+	}
+
+	sound, err := ctx.themeModel.Get(vs.UserID, vs.GuildID, kind)
+	if errors.Is(err, models.ErrDoesNotExist) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	g, err := s.State.Guild(vs.GuildID)
+	if err != nil {
+		return err
+	}
+
+	if kind == models.ThemeOutro && !hasListeners(g, channelID, vs.UserID, ctx.botID) {
+		return nil
+	}
+
+	if vc, joined := ctx.voice.connection(); joined && vc != nil && voiceChannelID(vc) != channelID {
+		return nil
+	}
+
+	if ok, _ := ctx.themeLimiter.Allow(fmt.Sprint(vs.UserID, ":", vs.GuildID, ":", kind)); !ok {
+		return nil
+	}
+
+	return ctx.streamToChannel(s, vs.GuildID, channelID, vs.UserID, sound)
+}
+
+// Bot will show, set or clear the author's intro or outro
+func (ctx *Context) updateTheme(s *discordgo.Session, m *discordgo.MessageCreate, kind models.ThemeKind, args []string) error {
+	if len(args) == 0 {
+		sound, err := ctx.themeModel.Get(m.Author.ID, m.GuildID, kind)
+		if errors.Is(err, models.ErrDoesNotExist) {
+			msg := fmt.Sprintf("<@%v> you don't have an %v, set one with **%v%v [SOUNDNAME]**",
+				m.Author.ID, kind, ctx.botCfg.CommandPrefix, kind)
+			_, _ = s.ChannelMessageSend(m.ChannelID, msg)
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		msg := fmt.Sprintf("<@%v> your %v is **%v%v**", m.Author.ID, kind, ctx.botCfg.CommandPrefix, sound.Name)
+		_, _ = s.ChannelMessageSend(m.ChannelID, msg)
+		return nil
+	}
+
+	if strings.ToLower(args[0]) == "off" {
+		err := ctx.themeModel.Clear(m.Author.ID, m.GuildID, kind)
+		if err != nil && !errors.Is(err, models.ErrDoesNotExist) {
+			return err
+		}
+
+		msg := fmt.Sprintf("<@%v> your %v has been removed", m.Author.ID, kind)
+		_, _ = s.ChannelMessageSend(m.ChannelID, msg)
+		return nil
+	}
+
+	if err := ctx.themeModel.Set(m.Author.ID, m.GuildID, kind, args[0]); err != nil {
+		return err
+	}
+
+	msg := fmt.Sprintf("<@%v> your %v is now **%v%v**", m.Author.ID, kind, ctx.botCfg.CommandPrefix, args[0])
+	_, _ = s.ChannelMessageSend(m.ChannelID, msg)
+	return nil
+}
+
+// Bot will turn intros and outros on or off for the guild
+func (ctx *Context) setThemesEnabled(s *discordgo.Session, m *discordgo.MessageCreate, enabled bool) error {
+	settings, err := ctx.settingsModel.Get(m.GuildID)
+	if err != nil {
+		return err
+	}
+
+	settings.ThemesEnabled = enabled
+	if err := ctx.settingsModel.Save(settings); err != nil {
+		return err
+	}
+
+	state := "off"
+	if enabled {
+		state = "on"
+	}
+
+	_, _ = s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Intros and outros are now **%v**", state))
+	return nil
+}
+
+// Wrapper function for the 'intro' command
+func (ctx *Context) introCommand() func(*discordgo.Session, *discordgo.MessageCreate, []string) error {
+	return func(s *discordgo.Session, m *discordgo.MessageCreate, st []string) error {
+		return ctx.updateTheme(s, m, models.ThemeIntro, st)
+	}
+}
+
+// Wrapper function for the 'outro' command
+func (ctx *Context) outroCommand() func(*discordgo.Session, *discordgo.MessageCreate, []string) error {
+	return func(s *discordgo.Session, m *discordgo.MessageCreate, st []string) error {
+		return ctx.updateTheme(s, m, models.ThemeOutro, st)
+	}
+}
+
+// Wrapper function for the 'themes' command
+func (ctx *Context) themesCommand() func(*discordgo.Session, *discordgo.MessageCreate, []string) error {
+	return func(s *discordgo.Session, m *discordgo.MessageCreate, st []string) error {
+		if len(st) < 1 {
+			ctx.help(s, m, "themes")
+			return ErrNotEnoughArgs
+		}
+
+		switch strings.ToLower(st[0]) {
+		case "on":
+			return ctx.setThemesEnabled(s, m, true)
+		case "off":
+			return ctx.setThemesEnabled(s, m, false)
+		}
+
+		ctx.help(s, m, "themes")
+		return ErrInvalidSubcommand
+	}
+}
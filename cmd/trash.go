@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tweekes0/pal-bot/internal/models"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	TRASH_PURGE_INTERVAL = time.Hour // How often soundbites past the retention period are purged
+	MAX_TRASH_LISTED     = 20        // The maximum number of soundbites listed in a 'trash' reply
+)
+
+// Gets how long a deleted soundbite stays in the trash
+func (ctx *Context) trashRetention() time.Duration {
+	return time.Duration(ctx.botCfg.Trash.Retention) * 24 * time.Hour
+}
+
+// Bot will delete the soundbites that have been in the trash longer than the
+// retention period, along with their files
+func (ctx *Context) purgeTrash(s *discordgo.Session) (int, error) {
+	expired, err := ctx.soundbiteModel.Trashed(ctx.clock.Now().Add(-ctx.trashRetention()))
+	if err != nil {
+		return 0, err
+	}
+
+	for _, sound := range expired {
+		if err := ctx.purgeSound(sound); err != nil {
+			return 0, err
+		}
+
+		ctx.record(s, &models.AuditEntry{Action: models.AuditPurge, SoundID: sound.ID, Sound: sound.Name})
+	}
+
+	return len(expired), nil
+}
+
+// Bot will purge the trash every TRASH_PURGE_INTERVAL until it stops
+func (ctx *Context) schedulePurge(s *discordgo.Session) {
+	ctx.clock.AfterFunc(TRASH_PURGE_INTERVAL, func() {
+		if n, err := ctx.purgeTrash(s); err != nil {
+			ctx.errorLogger.Println(err)
+		} else if n > 0 {
+			ctx.infoLogger.Printf("Purged %v soundbites from the trash\n", n)
+		}
+
+		ctx.schedulePurge(s)
+	})
+}
+
+// Bot will list the soundbites in the trash and when they will be purged
+func (ctx *Context) listTrash(s *discordgo.Session, m *discordgo.MessageCreate) error {
+	trashed, err := ctx.soundbiteModel.Trashed(time.Time{})
+	if err != nil {
+		return err
+	}
+
+	if len(trashed) == 0 {
+		_, _ = s.ChannelMessageSend(m.ChannelID, "the trash is empty :)")
+		return nil
+	}
+
+	var b strings.Builder
+	fmt.Fprint(&b, "**Trash:** \n")
+	for i, sound := range trashed {
+		if i == MAX_TRASH_LISTED {
+			fmt.Fprintf(&b, "...and %v more\n", len(trashed)-i)
+			break
+		}
+
+		purged := sound.Deleted.Add(ctx.trashRetention())
+		fmt.Fprintf(&b, "%v by %v, purged <t:%v:R>\n", sound.Name, sound.Username, purged.Unix())
+	}
+
+	_, _ = s.ChannelMessageSend(m.ChannelID, b.String())
+	return nil
+}
+
+// Bot will take a soundbite the author created out of the trash, mods can restore any soundbite
+func (ctx *Context) restoreSound(s *discordgo.Session, m *discordgo.MessageCreate, name string) error {
+	sound, err := ctx.soundbiteModel.GetTrashed(name)
+	if err != nil {
+		return err
+	}
+
+	if err := ctx.authorizeSoundbite(s, m, sound); err != nil {
+		return err
+	}
+
+	if err := ctx.soundbiteModel.Restore(sound.Name); err != nil {
+		return err
+	}
+
+	ctx.record(s, auditEntry(m, models.AuditRestore, sound))
+
+	msg := fmt.Sprintf("%v has been restored, play it with **%v%v**", sound.Name, ctx.botCfg.CommandPrefix, sound.Name)
+	_, _ = s.ChannelMessageSend(m.ChannelID, msg)
+	return nil
+}
+
+// Wrapper function for the 'trash' command
+func (ctx *Context) trashCommand() func(*discordgo.Session, *discordgo.MessageCreate, []string) error {
+	return func(s *discordgo.Session, m *discordgo.MessageCreate, st []string) error {
+		return ctx.listTrash(s, m)
+	}
+}
+
+// Wrapper function for the 'restore' command
+func (ctx *Context) restoreCommand() func(*discordgo.Session, *discordgo.MessageCreate, []string) error {
+	return func(s *discordgo.Session, m *discordgo.MessageCreate, st []string) error {
+		if len(st) < 1 {
+			ctx.help(s, m, "restore")
+			return ErrNotEnoughArgs
+		}
+
+		return ctx.restoreSound(s, m, st[0])
+	}
+}
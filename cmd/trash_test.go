@@ -0,0 +1,55 @@
+package main
+
+import (
+	"io/ioutil"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/tweekes0/pal-bot/config"
+	"github.com/tweekes0/pal-bot/internal/clock"
+	"github.com/tweekes0/pal-bot/internal/models"
+	test "github.com/tweekes0/pal-bot/internal/testing"
+)
+
+func TestPurgeTrash(t *testing.T) {
+	ctx, teardown := stateTestSetup(t)
+	defer teardown()
+
+	// soundbites are trashed at the real time
+	c := clock.NewFake(time.Now())
+	ctx.clock = c
+	ctx.botCfg = &config.BotConfig{Trash: config.TrashConfig{Retention: 1}}
+	ctx.errorLogger = log.New(ioutil.Discard, "", 0)
+	ctx.infoLogger = log.New(ioutil.Discard, "", 0)
+
+	ctx.auditModel = &models.AuditModel{DB: ctx.soundbiteModel.DB}
+	if err := ctx.auditModel.Initialize(); err != nil {
+		t.Fatalf("failed to initialize audit log: %v", err)
+	}
+
+	_ = ctx.soundbiteModel.Trash("sound0")
+	ctx.schedulePurge(nil)
+
+	c.Advance(23 * time.Hour)
+
+	_, err := ctx.soundbiteModel.GetTrashed("sound0")
+	test.AssertError(t, err, nil)
+
+	c.Advance(2 * time.Hour)
+
+	_, err = ctx.soundbiteModel.GetTrashed("sound0")
+	test.AssertError(t, err, models.ErrDoesNotExist)
+
+	_, err = ctx.soundbiteModel.Get("sound1")
+	test.AssertError(t, err, nil)
+
+	entries, _ := ctx.auditModel.List(models.AuditOptions{Sound: "sound0", Limit: 10})
+	if len(entries) != 1 || entries[0].Action != models.AuditPurge {
+		t.Errorf("expected the purge to be in the audit log, got %v entries", len(entries))
+	}
+
+	if c.Pending() != 1 {
+		t.Errorf("expected the next purge to be scheduled, got %v pending", c.Pending())
+	}
+}
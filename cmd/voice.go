@@ -0,0 +1,68 @@
+package main
+
+import (
+	"time"
+
+	"github.com/tweekes0/pal-bot/internal/player"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// How the bot's voice connections are watched for dropped connections
+var voiceSupervision = player.SupervisorConfig{
+	Interval:    5 * time.Second,
+	SendTimeout: 2 * time.Second,
+	Attempts:    3,
+	Backoff:     time.Second,
+}
+
+// Gets the player output of a voice connection
+func voiceOutput(vc *discordgo.VoiceConnection) player.Output {
+	return player.Output{
+		Send:     vc.OpusSend,
+		Speaking: vc.Speaking,
+		Ready: func() bool {
+			vc.RLock()
+			defer vc.RUnlock()
+
+			return vc.Ready
+		},
+	}
+}
+
+// Gets the channel a voice connection is in, discordgo changes it when the bot moves
+func voiceChannelID(vc *discordgo.VoiceConnection) string {
+	vc.RLock()
+	defer vc.RUnlock()
+
+	return vc.ChannelID
+}
+
+// Bot will join the voice channel of a connection that died again
+func (ctx *Context) rejoinVoice(s *discordgo.Session, vc *discordgo.VoiceConnection) (player.Output, error) {
+	vc.RLock()
+	guildID, channelID := vc.GuildID, vc.ChannelID
+	vc.RUnlock()
+
+	ctx.infoLogger.Printf("rejoining voice channel %v", channelID)
+	rejoined, err := s.ChannelVoiceJoin(guildID, channelID, false, true)
+	if err != nil {
+		return player.Output{}, err
+	}
+	ctx.voice.rejoined(vc, rejoined)
+
+	return voiceOutput(rejoined), nil
+}
+
+// Bot will give up on a voice connection that could not be rejoined
+func (ctx *Context) voiceLost(vc *discordgo.VoiceConnection, err error) {
+	ctx.errorLogger.Printf("giving up on voice channel %v: %v", voiceChannelID(vc), err)
+
+	if ctx.voice.leave(vc) == nil {
+		return
+	}
+
+	if err := vc.Disconnect(); err != nil {
+		ctx.errorLogger.Println(err)
+	}
+}
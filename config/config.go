@@ -18,9 +18,79 @@ const (
 
 // Struct for all the config elements found in 'config.toml'
 type BotConfig struct {
-	DiscordToken  string `toml:"DiscordToken"`
-	CommandPrefix string `toml:"CommandPrefix"`
-	BotChannelID  string `toml:BotChannelID`
+	DiscordToken  string                 `toml:"DiscordToken"`
+	CommandPrefix string                 `toml:"CommandPrefix"`
+	BotChannelID  string                 `toml:"BotChannelID"`
+	Library       LibraryConfig          `toml:"Library"`
+	RateLimit     RateLimitConfig        `toml:"RateLimit"`
+	Quota         QuotaConfig            `toml:"Quota"`
+	Suggestions   SuggestionsConfig      `toml:"Suggestions"`
+	Themes        ThemesConfig           `toml:"Themes"`
+	Voice         VoiceConfig            `toml:"Voice"`
+	Trash         TrashConfig            `toml:"Trash"`
+	Audit         AuditConfig            `toml:"Audit"`
+	Guilds        map[string]GuildConfig `toml:"Guilds"` // Settings for each guild keyed by guild ID
+}
+
+// Struct for the settings of a single guild
+type GuildConfig struct {
+	AdminRoleIDs []string `toml:"AdminRoleIDs"` // Roles that can use every command
+	ModRoleIDs   []string `toml:"ModRoleIDs"`   // Roles that can manage other users' soundbites
+}
+
+// Struct for the library consistency check settings
+type LibraryConfig struct {
+	CheckOnStartup  bool `toml:"CheckOnStartup"`  // Check the library for missing, orphaned and corrupt files at startup
+	RepairOnStartup bool `toml:"RepairOnStartup"` // Repair any issues found by the startup check
+}
+
+// Struct for the rate limits on playing and creating soundbites
+type RateLimitConfig struct {
+	Playback LimitConfig `toml:"Playback"`
+	Creation LimitConfig `toml:"Creation"`
+}
+
+// Struct for a single rate limit, a zero Burst or Interval disables the limit
+type LimitConfig struct {
+	Burst    int `toml:"Burst"`    // The number of times a command can be used in a row
+	Interval int `toml:"Interval"` // Time in seconds until the command can be used once more
+}
+
+// Struct for the limits on the soundbites each user can create
+type QuotaConfig struct {
+	QuotaLimit
+	Roles map[string]QuotaLimit `toml:"Roles"` // Overrides for members with these roles keyed by role ID
+}
+
+// Struct for a single quota, zero means unlimited
+type QuotaLimit struct {
+	MaxSounds  int `toml:"MaxSounds"`  // The number of soundbites a user can create
+	MaxSeconds int `toml:"MaxSeconds"` // The total length in seconds of a user's soundbites
+}
+
+// Struct for the settings of suggestions for unknown commands and sounds
+type SuggestionsConfig struct {
+	AutoPlay bool `toml:"AutoPlay"` // Play the suggested sound when it is the only one and just one typo away
+}
+
+// Struct for the settings of the intro and outro sounds played when members join or leave voice
+type ThemesConfig struct {
+	Cooldown int `toml:"Cooldown"` // Time in seconds before a member's intro or outro plays again, 0 disables it
+}
+
+// Struct for the settings of the bot's voice connections
+type VoiceConfig struct {
+	IdleTimeout int `toml:"IdleTimeout"` // Time in minutes without playing anything before the bot leaves voice, 0 disables it
+}
+
+// Struct for the settings of the trash that deleted soundbites are moved to
+type TrashConfig struct {
+	Retention int `toml:"Retention"` // Time in days a deleted soundbite can be restored before it is purged, 0 disables the trash
+}
+
+// Struct for the settings of the audit log of changes to soundbites
+type AuditConfig struct {
+	ChannelID string `toml:"ChannelID"` // Channel every audit log entry is posted to as well, empty only keeps them in the db
 }
 
 // Reads a config file and unmarshalls all of the entries into a *BotConfig struct
@@ -10,6 +10,7 @@ require (
 	github.com/kkdai/youtube/v2 v2.7.16
 	github.com/mattn/go-sqlite3 v1.14.14
 	github.com/u2takey/ffmpeg-go v0.4.1
+	layeh.com/gopus v0.0.0-20210501142526-1ee02d434e32
 )
 
 require (
@@ -0,0 +1,141 @@
+package clock
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// A Clock tells the time and runs functions after a delay, it is
+// replaced with a Fake in tests so time only moves when they say so
+type Clock interface {
+	Now() time.Time
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+// A Timer is a function waiting to run that can be stopped
+type Timer interface {
+	Stop() bool
+}
+
+// Clock that uses the system time
+type realClock struct{}
+
+// Creates a Clock that uses the system time
+func New() Clock {
+	return realClock{}
+}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) AfterFunc(d time.Duration, f func()) Timer {
+	return time.AfterFunc(d, f)
+}
+
+// Clock that only moves when Advance is called, functions run on the goroutine calling Advance
+type Fake struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+// Timer of a Fake clock
+type fakeTimer struct {
+	clock   *Fake
+	when    time.Time
+	f       func()
+	stopped bool
+}
+
+// Creates a Fake clock that starts at the given time
+func NewFake(now time.Time) *Fake {
+	return &Fake{now: now}
+}
+
+func (c *Fake) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *Fake) AfterFunc(d time.Duration, f func()) Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t := &fakeTimer{clock: c, when: c.now.Add(d), f: f}
+	c.timers = append(c.timers, t)
+
+	return t
+}
+
+// Moves the clock forward, running every function that is due in the order they are due
+func (c *Fake) Advance(d time.Duration) {
+	c.mu.Lock()
+	end := c.now.Add(d)
+	c.mu.Unlock()
+
+	for {
+		c.mu.Lock()
+		t := c.due(end)
+		if t == nil {
+			c.now = end
+			c.mu.Unlock()
+			return
+		}
+
+		c.now = t.when
+		t.stopped = true
+		c.mu.Unlock()
+
+		t.f()
+	}
+}
+
+// Gets the first timer due by the given time, the caller must hold the lock
+func (c *Fake) due(end time.Time) *fakeTimer {
+	pending := c.timers[:0]
+	for _, t := range c.timers {
+		if !t.stopped {
+			pending = append(pending, t)
+		}
+	}
+	c.timers = pending
+
+	sort.SliceStable(c.timers, func(i, j int) bool {
+		return c.timers[i].when.Before(c.timers[j].when)
+	})
+
+	if len(c.timers) == 0 || c.timers[0].when.After(end) {
+		return nil
+	}
+
+	return c.timers[0]
+}
+
+// Gets the number of functions waiting to run
+func (c *Fake) Pending() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	n := 0
+	for _, t := range c.timers {
+		if !t.stopped {
+			n++
+		}
+	}
+
+	return n
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	stopped := t.stopped
+	t.stopped = true
+
+	return !stopped
+}
@@ -0,0 +1,75 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	test "github.com/tweekes0/pal-bot/internal/testing"
+)
+
+var start = time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestFake(t *testing.T) {
+	t.Run("advance moves the time", func(t *testing.T) {
+		t.Parallel()
+
+		c := NewFake(start)
+		c.Advance(time.Minute)
+
+		test.AssertType(t, c.Now(), start.Add(time.Minute))
+	})
+
+	t.Run("run functions in the order they are due", func(t *testing.T) {
+		t.Parallel()
+
+		c := NewFake(start)
+		ran := []string{}
+		c.AfterFunc(2*time.Second, func() { ran = append(ran, "second") })
+		c.AfterFunc(time.Second, func() { ran = append(ran, "first") })
+		c.AfterFunc(time.Minute, func() { ran = append(ran, "later") })
+
+		c.Advance(5 * time.Second)
+		test.AssertType(t, ran, []string{"first", "second"})
+		test.AssertType(t, c.Pending(), 1)
+	})
+
+	t.Run("functions see the time they were due", func(t *testing.T) {
+		t.Parallel()
+
+		c := NewFake(start)
+		var at time.Time
+		c.AfterFunc(time.Second, func() { at = c.Now() })
+
+		c.Advance(time.Minute)
+		test.AssertType(t, at, start.Add(time.Second))
+	})
+
+	t.Run("functions scheduled while advancing run if due", func(t *testing.T) {
+		t.Parallel()
+
+		c := NewFake(start)
+		ran := 0
+		c.AfterFunc(time.Second, func() {
+			ran++
+			c.AfterFunc(time.Second, func() { ran++ })
+		})
+
+		c.Advance(3 * time.Second)
+		test.AssertType(t, ran, 2)
+	})
+
+	t.Run("stopped functions do not run", func(t *testing.T) {
+		t.Parallel()
+
+		c := NewFake(start)
+		ran := false
+		timer := c.AfterFunc(time.Second, func() { ran = true })
+
+		test.AssertType(t, timer.Stop(), true)
+		test.AssertType(t, timer.Stop(), false)
+
+		c.Advance(time.Minute)
+		test.AssertType(t, ran, false)
+		test.AssertType(t, c.Pending(), 0)
+	})
+}
@@ -0,0 +1,95 @@
+package fuzzy
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Struct for a candidate and its edit distance from a query
+type Match struct {
+	Value    string
+	Distance int
+}
+
+// Calculates the Levenshtein distance between two strings, ignoring case
+func Distance(a, b string) int {
+	ra := []rune(strings.ToLower(a))
+	rb := []rune(strings.ToLower(b))
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
+
+// Gets the largest distance at which a candidate is still considered
+// close to the query, roughly one typo for every three characters
+func MaxDistance(query string) int {
+	d := utf8.RuneCountInString(query) / 3
+	if d < 1 {
+		return 1
+	}
+
+	return d
+}
+
+// Finds up to n candidates within maxDistance of the query, closest first.
+// Candidates with the same distance are sorted alphabetically.
+func Closest(query string, candidates []string, maxDistance, n int) []Match {
+	matches := []Match{}
+	seen := make(map[string]bool)
+
+	for _, c := range candidates {
+		if seen[c] {
+			continue
+		}
+		seen[c] = true
+
+		if d := Distance(query, c); d <= maxDistance {
+			matches = append(matches, Match{Value: c, Distance: d})
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Distance != matches[j].Distance {
+			return matches[i].Distance < matches[j].Distance
+		}
+
+		return matches[i].Value < matches[j].Value
+	})
+
+	if len(matches) > n {
+		matches = matches[:n]
+	}
+
+	return matches
+}
+
+func min(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+
+	return m
+}
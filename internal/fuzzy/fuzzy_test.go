@@ -0,0 +1,98 @@
+package fuzzy
+
+import (
+	"testing"
+
+	test "github.com/tweekes0/pal-bot/internal/testing"
+)
+
+func TestDistance(t *testing.T) {
+	tt := []struct {
+		description string
+		a           string
+		b           string
+		expected    int
+	}{
+		{
+			description: "distance between equal strings",
+			a:           "airhorn",
+			b:           "airhorn",
+			expected:    0,
+		},
+		{
+			description: "distance between strings with different case",
+			a:           "AirHorn",
+			b:           "airhorn",
+			expected:    0,
+		},
+		{
+			description: "distance with swapped characters",
+			a:           "airhron",
+			b:           "airhorn",
+			expected:    2,
+		},
+		{
+			description: "distance with missing character",
+			a:           "airhrn",
+			b:           "airhorn",
+			expected:    1,
+		},
+		{
+			description: "distance from empty string",
+			a:           "",
+			b:           "bruh",
+			expected:    4,
+		},
+		{
+			description: "distance between multibyte strings",
+			a:           "café",
+			b:           "cafe",
+			expected:    1,
+		},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			test.AssertType(t, Distance(tc.a, tc.b), tc.expected)
+			test.AssertType(t, Distance(tc.b, tc.a), tc.expected)
+		})
+	}
+}
+
+func TestMaxDistance(t *testing.T) {
+	test.AssertType(t, MaxDistance(""), 1)
+	test.AssertType(t, MaxDistance("bruh"), 1)
+	test.AssertType(t, MaxDistance("airhorn"), 2)
+	test.AssertType(t, MaxDistance("jigglypuff"), 3)
+}
+
+func TestClosest(t *testing.T) {
+	candidates := []string{"airhorn", "airhorns", "bruh", "horn", "airhorn"}
+
+	t.Run("closest candidates first", func(t *testing.T) {
+		got := Closest("airhron", candidates, 2, 5)
+		test.AssertType(t, got, []Match{
+			{Value: "airhorn", Distance: 2},
+		})
+
+		got = Closest("airhorn", candidates, 1, 5)
+		test.AssertType(t, got, []Match{
+			{Value: "airhorn", Distance: 0},
+			{Value: "airhorns", Distance: 1},
+		})
+	})
+
+	t.Run("closest candidates limited to n", func(t *testing.T) {
+		got := Closest("airhorn", candidates, 5, 1)
+		test.AssertType(t, got, []Match{
+			{Value: "airhorn", Distance: 0},
+		})
+	})
+
+	t.Run("no close candidates", func(t *testing.T) {
+		got := Closest("rimshot", candidates, 2, 5)
+		test.AssertType(t, got, []Match{})
+	})
+}
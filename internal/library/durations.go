@@ -0,0 +1,38 @@
+package library
+
+import (
+	"errors"
+
+	"github.com/tweekes0/pal-bot/internal/models"
+	"github.com/tweekes0/pal-bot/internal/sounds"
+)
+
+// Stores the duration of soundbites created before durations were recorded.
+// Soundbites with missing or corrupt files are skipped, they are left for Check.
+// Returns the number of soundbites that were updated.
+func BackfillDurations(m *models.SoundbiteModel) (int, error) {
+	soundbites, err := m.GetAll()
+	if err != nil && !errors.Is(err, models.ErrNoRecords) {
+		return 0, err
+	}
+
+	n := 0
+	for _, s := range soundbites {
+		if s.Duration != 0 {
+			continue
+		}
+
+		d, err := sounds.DCADuration(s.FilePath)
+		if err != nil {
+			continue
+		}
+
+		if err := m.UpdateDuration(s.Name, d); err != nil {
+			return n, err
+		}
+
+		n++
+	}
+
+	return n, nil
+}
@@ -0,0 +1,41 @@
+package library
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	test "github.com/tweekes0/pal-bot/internal/testing"
+)
+
+func TestBackfillDurations(t *testing.T) {
+	t.Run("backfill soundbites without a duration", func(t *testing.T) {
+		m, dir, teardown := libraryTestSetup(t)
+		defer teardown()
+
+		insertTestSoundbite(t, m, "test1", writeTestDCA(t, dir, "test1", false))
+		_ = m.UpdateDuration("test1", 0)
+
+		missing := writeTestDCA(t, dir, "missing", false)
+		insertTestSoundbite(t, m, "missing", missing)
+		_ = m.UpdateDuration("missing", 0)
+		os.Remove(missing)
+
+		n, err := BackfillDurations(m)
+		test.AssertError(t, err, nil)
+		test.AssertType(t, n, 1)
+
+		s, err := m.Get("test1")
+		test.AssertError(t, err, nil)
+		test.AssertType(t, s.Duration, 60*time.Millisecond)
+	})
+
+	t.Run("backfill empty library", func(t *testing.T) {
+		m, _, teardown := libraryTestSetup(t)
+		defer teardown()
+
+		n, err := BackfillDurations(m)
+		test.AssertError(t, err, nil)
+		test.AssertType(t, n, 0)
+	})
+}
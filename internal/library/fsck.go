@@ -0,0 +1,192 @@
+package library
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/tweekes0/pal-bot/internal/models"
+	"github.com/tweekes0/pal-bot/internal/sounds"
+)
+
+const (
+	// Orphaned files younger than this are skipped, they are most likely
+	// a clip or upload that has not been inserted into the db yet.
+	ORPHAN_GRACE_PERIOD = time.Minute
+)
+
+// The kinds of inconsistencies that can be found in the library
+type IssueKind int
+
+const (
+	MissingFile IssueKind = iota
+	OrphanFile
+	HashMismatch
+	CorruptFile
+)
+
+func (k IssueKind) String() string {
+	switch k {
+	case MissingFile:
+		return "missing file"
+	case OrphanFile:
+		return "orphan file"
+	case HashMismatch:
+		return "hash mismatch"
+	case CorruptFile:
+		return "corrupt file"
+	}
+
+	return "unknown"
+}
+
+// Struct for a single inconsistency between the 'soundbites' table and the audio folder
+type Issue struct {
+	Kind     IssueKind
+	SoundID  int    // ID of the soundbite, 0 for orphan files
+	Name     string // Name of the soundbite, empty for orphan files
+	FilePath string
+	FileHash string // Hash of the file on disk, only set for hash mismatches
+	Repaired bool
+}
+
+func (i Issue) String() string {
+	if i.Name == "" {
+		return fmt.Sprintf("%v: %v", i.Kind, i.FilePath)
+	}
+
+	return fmt.Sprintf("%v: %v (%v)", i.Kind, i.Name, i.FilePath)
+}
+
+// Struct that holds the results of a library check
+type Report struct {
+	Checked int
+	Issues  []*Issue
+}
+
+// Returns the number of issues that have been repaired
+func (r *Report) Repaired() int {
+	n := 0
+	for _, i := range r.Issues {
+		if i.Repaired {
+			n++
+		}
+	}
+
+	return n
+}
+
+// Checks that every soundbite has a readable and valid DCA file that matches its
+// stored hash and that every DCA file in dir belongs to a soundbite.
+func Check(m *models.SoundbiteModel, dir string) (*Report, error) {
+	soundbites, err := m.GetAll()
+	if err != nil && !errors.Is(err, models.ErrNoRecords) {
+		return nil, err
+	}
+
+	r := &Report{Checked: len(soundbites)}
+	known := make(map[string]bool)
+
+	for _, s := range soundbites {
+		known[filepath.Clean(s.FilePath)] = true
+
+		if issue := checkSoundbite(s); issue != nil {
+			r.Issues = append(r.Issues, issue)
+		}
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".dca" {
+			continue
+		}
+
+		path := filepath.Join(dir, e.Name())
+		if known[filepath.Clean(path)] {
+			continue
+		}
+
+		info, err := e.Info()
+		if err != nil {
+			return nil, err
+		}
+
+		if time.Since(info.ModTime()) < ORPHAN_GRACE_PERIOD {
+			continue
+		}
+
+		r.Issues = append(r.Issues, &Issue{Kind: OrphanFile, FilePath: path})
+	}
+
+	return r, nil
+}
+
+// Checks a single soundbite's file, returns nil if the file is consistent
+func checkSoundbite(s *models.Soundbite) *Issue {
+	issue := &Issue{SoundID: s.ID, Name: s.Name, FilePath: s.FilePath}
+
+	if _, err := sounds.ValidateDCA(s.FilePath); err != nil {
+		if os.IsNotExist(err) {
+			issue.Kind = MissingFile
+		} else {
+			issue.Kind = CorruptFile
+		}
+
+		return issue
+	}
+
+	hash, err := sounds.HashFile(s.FilePath)
+	if err != nil {
+		issue.Kind = MissingFile
+		return issue
+	}
+
+	if !strings.EqualFold(hash, s.FileHash) {
+		issue.Kind = HashMismatch
+		issue.FileHash = hash
+		return issue
+	}
+
+	return nil
+}
+
+// Repairs the issues found by Check. Soundbites with missing files are deleted, soundbites
+// with corrupt files are moved to the trash so their file is kept until they are restored or
+// purged, and orphan files are removed. Hash mismatches are left for an admin to look into,
+// the file is valid but there is no telling whether it or the stored hash is the right one.
+// Issues that were already fixed by something else are not marked as repaired.
+func Repair(m *models.SoundbiteModel, r *Report) error {
+	for _, i := range r.Issues {
+		var err error
+
+		switch i.Kind {
+		case MissingFile:
+			err = m.ForceDelete(i.Name)
+		case CorruptFile:
+			err = m.Trash(i.Name)
+		case OrphanFile:
+			err = sounds.DeleteFile(i.FilePath)
+		default:
+			continue
+		}
+
+		if errors.Is(err, models.ErrDoesNotExist) || os.IsNotExist(err) {
+			continue
+		}
+
+		if err != nil {
+			return err
+		}
+
+		i.Repaired = true
+	}
+
+	return nil
+}
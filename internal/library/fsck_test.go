@@ -0,0 +1,230 @@
+package library
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/binary"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/tweekes0/pal-bot/config"
+	"github.com/tweekes0/pal-bot/internal/models"
+	"github.com/tweekes0/pal-bot/internal/sounds"
+	test "github.com/tweekes0/pal-bot/internal/testing"
+)
+
+func libraryTestSetup(t *testing.T) (*models.SoundbiteModel, string, func()) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "*")
+	if err != nil {
+		log.Fatalf("failed to create dir: %v", err)
+	}
+
+	db, err := sql.Open(config.DB_DRIVER, filepath.Join(dir, "test.db"))
+	if err != nil {
+		log.Fatalf("failed to open db: %v", err)
+	}
+
+	m := &models.SoundbiteModel{DB: db}
+	m.Initialize()
+	teardown := func() {
+		db.Close()
+		os.RemoveAll(dir)
+	}
+
+	return m, dir, teardown
+}
+
+// Writes a DCA file with a few frames, corrupt files end with a truncated frame
+func writeTestDCA(t *testing.T, dir, name string, corrupt bool) string {
+	var buf bytes.Buffer
+	frame := bytes.Repeat([]byte{0xfc}, 120)
+
+	for i := 0; i < 3; i++ {
+		_ = binary.Write(&buf, binary.LittleEndian, int16(len(frame)))
+		buf.Write(frame)
+	}
+
+	if corrupt {
+		_ = binary.Write(&buf, binary.LittleEndian, int16(len(frame)))
+	}
+
+	path := filepath.Join(dir, name+".dca")
+	if err := ioutil.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	old := time.Now().Add(-2 * ORPHAN_GRACE_PERIOD)
+	_ = os.Chtimes(path, old, old)
+
+	return path
+}
+
+func insertTestSoundbite(t *testing.T, m *models.SoundbiteModel, name, path string) {
+	hash, _ := sounds.HashFile(path)
+	s := &models.Soundbite{
+		Name:     name,
+		Username: "test_username",
+		UserID:   "111111",
+		FilePath: path,
+		FileHash: hash,
+		Duration: 60 * time.Millisecond,
+	}
+
+	if _, err := m.Insert(s); err != nil {
+		t.Fatalf("failed to insert soundbite: %v", err)
+	}
+}
+
+func issueKinds(r *Report) map[string]IssueKind {
+	kinds := make(map[string]IssueKind)
+	for _, i := range r.Issues {
+		kinds[filepath.Base(i.FilePath)] = i.Kind
+	}
+
+	return kinds
+}
+
+func TestCheck(t *testing.T) {
+	t.Run("check empty library", func(t *testing.T) {
+		m, dir, teardown := libraryTestSetup(t)
+		defer teardown()
+
+		r, err := Check(m, dir)
+		test.AssertError(t, err, nil)
+		test.AssertType(t, r.Checked, 0)
+		test.AssertType(t, len(r.Issues), 0)
+	})
+
+	t.Run("check consistent library", func(t *testing.T) {
+		m, dir, teardown := libraryTestSetup(t)
+		defer teardown()
+
+		insertTestSoundbite(t, m, "test1", writeTestDCA(t, dir, "test1", false))
+		insertTestSoundbite(t, m, "test2", writeTestDCA(t, dir, "test2", false))
+
+		r, err := Check(m, dir)
+		test.AssertError(t, err, nil)
+		test.AssertType(t, r.Checked, 2)
+		test.AssertType(t, len(r.Issues), 0)
+	})
+
+	t.Run("check inconsistent library", func(t *testing.T) {
+		m, dir, teardown := libraryTestSetup(t)
+		defer teardown()
+
+		missing := writeTestDCA(t, dir, "missing", false)
+		insertTestSoundbite(t, m, "missing", missing)
+		os.Remove(missing)
+
+		mismatch := writeTestDCA(t, dir, "mismatch", false)
+		insertTestSoundbite(t, m, "mismatch", mismatch)
+		_ = m.UpdateHash("mismatch", "sha256:111111")
+
+		corrupt := writeTestDCA(t, dir, "corrupt", true)
+		insertTestSoundbite(t, m, "corrupt", corrupt)
+
+		writeTestDCA(t, dir, "orphan", false)
+
+		r, err := Check(m, dir)
+		test.AssertError(t, err, nil)
+		test.AssertType(t, r.Checked, 3)
+		test.AssertType(t, issueKinds(r), map[string]IssueKind{
+			"missing.dca":  MissingFile,
+			"mismatch.dca": HashMismatch,
+			"corrupt.dca":  CorruptFile,
+			"orphan.dca":   OrphanFile,
+		})
+	})
+
+	t.Run("check skips recently created files", func(t *testing.T) {
+		m, dir, teardown := libraryTestSetup(t)
+		defer teardown()
+
+		path := writeTestDCA(t, dir, "recent", false)
+		_ = os.Chtimes(path, time.Now(), time.Now())
+
+		r, err := Check(m, dir)
+		test.AssertError(t, err, nil)
+		test.AssertType(t, len(r.Issues), 0)
+	})
+}
+
+func TestRepair(t *testing.T) {
+	t.Run("repair inconsistent library", func(t *testing.T) {
+		m, dir, teardown := libraryTestSetup(t)
+		defer teardown()
+
+		missing := writeTestDCA(t, dir, "missing", false)
+		insertTestSoundbite(t, m, "missing", missing)
+		os.Remove(missing)
+
+		mismatch := writeTestDCA(t, dir, "mismatch", false)
+		insertTestSoundbite(t, m, "mismatch", mismatch)
+		_ = m.UpdateHash("mismatch", "sha256:111111")
+
+		corrupt := writeTestDCA(t, dir, "corrupt", true)
+		insertTestSoundbite(t, m, "corrupt", corrupt)
+
+		orphan := writeTestDCA(t, dir, "orphan", false)
+
+		r, err := Check(m, dir)
+		test.AssertError(t, err, nil)
+
+		err = Repair(m, r)
+		test.AssertError(t, err, nil)
+		test.AssertType(t, r.Repaired(), 3)
+
+		if _, err := os.Stat(orphan); !os.IsNotExist(err) {
+			t.Fatalf("got: %v, expected: %v", err, os.ErrNotExist)
+		}
+
+		if _, err := os.Stat(corrupt); err != nil {
+			t.Fatalf("got: %v, expected: %v", err, nil)
+		}
+
+		_, err = m.GetTrashed("corrupt")
+		test.AssertError(t, err, nil)
+
+		s, err := m.Get("mismatch")
+		test.AssertError(t, err, nil)
+		test.AssertType(t, s.FileHash, "sha256:111111")
+
+		r, err = Check(m, dir)
+		test.AssertError(t, err, nil)
+		test.AssertType(t, r.Checked, 2)
+		test.AssertType(t, len(r.Issues), 2)
+
+		err = Repair(m, r)
+		test.AssertError(t, err, nil)
+		test.AssertType(t, r.Repaired(), 0)
+	})
+
+	t.Run("repair issues fixed in the meantime", func(t *testing.T) {
+		m, dir, teardown := libraryTestSetup(t)
+		defer teardown()
+
+		missing := writeTestDCA(t, dir, "missing", false)
+		insertTestSoundbite(t, m, "missing", missing)
+		os.Remove(missing)
+
+		orphan := writeTestDCA(t, dir, "orphan", false)
+
+		r, err := Check(m, dir)
+		test.AssertError(t, err, nil)
+		test.AssertType(t, len(r.Issues), 2)
+
+		_ = m.ForceDelete("missing")
+		os.Remove(orphan)
+
+		err = Repair(m, r)
+		test.AssertError(t, err, nil)
+		test.AssertType(t, r.Repaired(), 0)
+	})
+}
@@ -0,0 +1,178 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+)
+
+// Struct to present a record in the 'aliases' table, an alternate name for a soundbite
+type Alias struct {
+	Name      string
+	SoundName string
+	Username  string
+	UserID    string
+	Created   time.Time
+}
+
+// Struct that holds the database connectivity for the 'aliases' table
+type AliasModel struct {
+	DB *sql.DB
+}
+
+// Initialize the 'aliases' table in the sqlite db, the 'soundbites' table must already exist.
+// Aliases are removed along with their soundbite, and a name can only belong to either a
+// soundbite or an alias.
+func (m *AliasModel) Initialize() error {
+	stmt := `CREATE TABLE IF NOT EXISTS aliases (
+		id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL,
+		soundbite_id INTEGER NOT NULL REFERENCES soundbites(id),
+		username TEXT NOT NULL,
+		user_id TEXT NOT NULL,
+		created TEXT NOT NULL,
+		UNIQUE(name)
+	);
+	CREATE TRIGGER IF NOT EXISTS aliases_soundbite_delete AFTER DELETE ON soundbites
+	BEGIN
+		DELETE FROM aliases WHERE soundbite_id = OLD.id;
+	END;
+	CREATE TRIGGER IF NOT EXISTS aliases_name_insert BEFORE INSERT ON aliases
+	WHEN EXISTS(SELECT 1 FROM soundbites WHERE name = NEW.name)
+	BEGIN
+		SELECT RAISE(ABORT, 'UNIQUE constraint failed: soundbites.name');
+	END;
+	CREATE TRIGGER IF NOT EXISTS soundbites_alias_insert BEFORE INSERT ON soundbites
+	WHEN EXISTS(SELECT 1 FROM aliases WHERE name = NEW.name)
+	BEGIN
+		SELECT RAISE(ABORT, 'UNIQUE constraint failed: aliases.name');
+	END;
+	CREATE TRIGGER IF NOT EXISTS soundbites_alias_rename BEFORE UPDATE OF name ON soundbites
+	WHEN EXISTS(SELECT 1 FROM aliases WHERE name = NEW.name)
+	BEGIN
+		SELECT RAISE(ABORT, 'UNIQUE constraint failed: aliases.name');
+	END;`
+
+	if _, err := m.DB.Exec(stmt); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Adds an alias for a soundbite, the soundbite can be given by its name or another of its
+// aliases. Returns ErrUniqueConstraint if the name belongs to a soundbite or alias already.
+func (m *AliasModel) Insert(name, sound, username, uid string) error {
+	stmt := `INSERT INTO aliases (name, soundbite_id, username, user_id, created)
+	SELECT ?, id, ?, ?, datetime('now') FROM soundbites
+	WHERE deleted_at = '' AND (name = ? OR id = (SELECT soundbite_id FROM aliases WHERE name = ?));`
+
+	res, err := m.DB.Exec(stmt, name, username, uid, sound, sound)
+	if err != nil {
+		if strings.Contains(err.Error(), "UNIQUE constraint") {
+			return ErrUniqueConstraint
+		}
+
+		return err
+	}
+
+	c, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if int(c) == 0 {
+		return ErrDoesNotExist
+	}
+
+	return nil
+}
+
+// Scans a row of aliasColumns into an Alias
+func scanAlias(row scanner) (*Alias, error) {
+	var date string
+	a := &Alias{}
+
+	if err := row.Scan(&a.Name, &a.SoundName, &a.Username, &a.UserID, &date); err != nil {
+		return nil, err
+	}
+
+	t, err := time.Parse(TIME_LAYOUT, date)
+	if err != nil {
+		return nil, err
+	}
+
+	a.Created = t
+	return a, nil
+}
+
+// Columns scanned by scanAlias, 'a' is the aliases table and 's' the soundbites table
+const aliasColumns = `a.name, s.name, a.username, a.user_id, a.created`
+
+// Gets an alias by name
+func (m *AliasModel) Get(name string) (*Alias, error) {
+	stmt := `SELECT ` + aliasColumns + ` FROM aliases a
+	JOIN soundbites s ON s.id = a.soundbite_id WHERE a.name = ? AND s.deleted_at = '';`
+
+	a, err := scanAlias(m.DB.QueryRow(stmt, name))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrDoesNotExist
+		}
+
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// Gets the aliases of a soundbite in alphabetical order, every alias if sound is empty
+func (m *AliasModel) List(sound string) ([]*Alias, error) {
+	stmt := `SELECT ` + aliasColumns + ` FROM aliases a
+	JOIN soundbites s ON s.id = a.soundbite_id WHERE s.deleted_at = '' AND (? = '' OR s.name = ?)
+	ORDER BY a.name;`
+
+	rows, err := m.DB.Query(stmt, sound, sound)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	aliases := []*Alias{}
+	for rows.Next() {
+		a, err := scanAlias(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		aliases = append(aliases, a)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return aliases, nil
+}
+
+// Removes an alias, the soundbite is kept
+func (m *AliasModel) Delete(name string) error {
+	stmt := `DELETE FROM aliases WHERE name = ?;`
+
+	res, err := m.DB.Exec(stmt, name)
+	if err != nil {
+		return err
+	}
+
+	c, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if int(c) == 0 {
+		return ErrDoesNotExist
+	}
+
+	return nil
+}
@@ -0,0 +1,152 @@
+package models
+
+import (
+	"testing"
+
+	test "github.com/tweekes0/pal-bot/internal/testing"
+)
+
+func TestAliases(t *testing.T) {
+	t.Run("insert and get aliases", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		am := AliasModel{DB: m.DB}
+		initializeTestModels(t, &am)
+
+		_, _ = mockInsert(m, s1)
+
+		err := am.Insert("t1", s1.Name, s2.Username, s2.UserID)
+		test.AssertError(t, err, nil)
+
+		// an alias of an alias points to the same soundbite
+		err = am.Insert("t", "t1", s2.Username, s2.UserID)
+		test.AssertError(t, err, nil)
+
+		for _, name := range []string{"t1", "t"} {
+			a, err := am.Get(name)
+			test.AssertError(t, err, nil)
+			test.AssertType(t, a.SoundName, s1.Name)
+			test.AssertType(t, a.UserID, s2.UserID)
+		}
+
+		_, err = am.Get("missing")
+		test.AssertError(t, err, ErrDoesNotExist)
+	})
+
+	t.Run("insert alias of non-existent soundbite", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		am := AliasModel{DB: m.DB}
+		initializeTestModels(t, &am)
+
+		err := am.Insert("t1", s1.Name, s1.Username, s1.UserID)
+		test.AssertError(t, err, ErrDoesNotExist)
+	})
+
+	t.Run("names are unique across soundbites and aliases", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		am := AliasModel{DB: m.DB}
+		initializeTestModels(t, &am)
+
+		_, _ = mockInsert(m, s1)
+		_, _ = mockInsert(m, s2)
+		_ = am.Insert("t1", s1.Name, s1.Username, s1.UserID)
+
+		err := am.Insert("t1", s2.Name, s2.Username, s2.UserID)
+		test.AssertError(t, err, ErrUniqueConstraint)
+
+		err = am.Insert(s2.Name, s1.Name, s1.Username, s1.UserID)
+		test.AssertError(t, err, ErrUniqueConstraint)
+
+		_, err = m.Insert(&Soundbite{Name: "t1", FilePath: "/path/to/t1", FileHash: "sha256:t1"})
+		test.AssertError(t, err, ErrUniqueConstraint)
+
+		err = m.UpdateName(s2.Name, "t1")
+		test.AssertError(t, err, ErrUniqueConstraint)
+	})
+
+	t.Run("list aliases", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		am := AliasModel{DB: m.DB}
+		initializeTestModels(t, &am)
+
+		_, _ = mockInsert(m, s1)
+		_, _ = mockInsert(m, s2)
+		_ = am.Insert("b", s1.Name, s1.Username, s1.UserID)
+		_ = am.Insert("a", s1.Name, s1.Username, s1.UserID)
+		_ = am.Insert("c", s2.Name, s2.Username, s2.UserID)
+
+		tests := []struct {
+			name  string
+			sound string
+			want  []string
+		}{
+			{"aliases of a soundbite", s1.Name, []string{"a", "b"}},
+			{"every alias", "", []string{"a", "b", "c"}},
+			{"soundbite without aliases", s3.Name, []string{}},
+		}
+
+		for _, tc := range tests {
+			tc := tc
+			t.Run(tc.name, func(t *testing.T) {
+				aliases, err := am.List(tc.sound)
+				test.AssertError(t, err, nil)
+
+				names := []string{}
+				for _, a := range aliases {
+					names = append(names, a.Name)
+				}
+
+				test.AssertType(t, names, tc.want)
+			})
+		}
+	})
+
+	t.Run("aliases follow renames and are removed with their soundbite", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		am := AliasModel{DB: m.DB}
+		initializeTestModels(t, &am)
+
+		_, _ = mockInsert(m, s1)
+		_ = am.Insert("t1", s1.Name, s1.Username, s1.UserID)
+
+		_ = m.UpdateName(s1.Name, "renamed")
+
+		a, err := am.Get("t1")
+		test.AssertError(t, err, nil)
+		test.AssertType(t, a.SoundName, "renamed")
+
+		_ = m.ForceDelete("renamed")
+
+		_, err = am.Get("t1")
+		test.AssertError(t, err, ErrDoesNotExist)
+	})
+
+	t.Run("delete aliases", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		am := AliasModel{DB: m.DB}
+		initializeTestModels(t, &am)
+
+		_, _ = mockInsert(m, s1)
+		_ = am.Insert("t1", s1.Name, s1.Username, s1.UserID)
+
+		err := am.Delete("t1")
+		test.AssertError(t, err, nil)
+
+		err = am.Delete("t1")
+		test.AssertError(t, err, ErrDoesNotExist)
+
+		exists, _ := m.Exists(s1.Name, "")
+		test.AssertType(t, exists, true)
+	})
+}
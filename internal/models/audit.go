@@ -0,0 +1,137 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// What was done to a soundbite in an audit log entry
+type AuditAction string
+
+const (
+	AuditCreate   AuditAction = "create"
+	AuditRename   AuditAction = "rename"   // OldValue and NewValue are the old and new names
+	AuditDelete   AuditAction = "delete"   // Moved to the trash, or deleted for good when the trash is disabled
+	AuditRestore  AuditAction = "restore"  // Taken out of the trash
+	AuditPurge    AuditAction = "purge"    // Deleted for good after the retention period
+	AuditTag      AuditAction = "tag"      // NewValue is the tags that were added
+	AuditUntag    AuditAction = "untag"    // NewValue is the tags that were removed
+	AuditTransfer AuditAction = "transfer" // OldValue and NewValue are the old and new owners
+	AuditReclip   AuditAction = "reclip"   // OldValue and NewValue are the old and new clips of the video
+	AuditRepair   AuditAction = "repair"   // NewValue is the issue the library check repaired
+)
+
+// Struct to present a record in the 'audit_log' table
+type AuditEntry struct {
+	ID       int
+	Action   AuditAction
+	SoundID  int    // The soundbite's ID, kept after the soundbite is purged
+	Sound    string // The soundbite's name when the action was taken
+	UserID   string // Who took the action, empty when the bot took it by itself
+	Username string
+	GuildID  string
+	OldValue string
+	NewValue string
+	Created  time.Time
+}
+
+// Struct for the options used to list audit log entries
+type AuditOptions struct {
+	Sound  string // Only list entries of the soundbite with this name now or when the action was taken
+	UserID string // Only list entries of actions this user took
+	Limit  int
+}
+
+// Struct that holds the database connectivity for the 'audit_log' table
+type AuditModel struct {
+	DB *sql.DB
+}
+
+// Initialize the 'audit_log' table in the sqlite db, the 'soundbites' table must already exist.
+// Entries can only be added, they are never updated or deleted.
+func (m *AuditModel) Initialize() error {
+	stmt := `CREATE TABLE IF NOT EXISTS audit_log (
+		id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+		action TEXT NOT NULL,
+		sound_id INTEGER NOT NULL,
+		sound TEXT NOT NULL,
+		user_id TEXT NOT NULL,
+		username TEXT NOT NULL,
+		guild_id TEXT NOT NULL,
+		old_value TEXT NOT NULL,
+		new_value TEXT NOT NULL,
+		created TEXT NOT NULL
+	);
+	CREATE TRIGGER IF NOT EXISTS audit_log_update BEFORE UPDATE ON audit_log
+	BEGIN
+		SELECT RAISE(ABORT, 'audit log is append-only');
+	END;
+	CREATE TRIGGER IF NOT EXISTS audit_log_delete BEFORE DELETE ON audit_log
+	BEGIN
+		SELECT RAISE(ABORT, 'audit log is append-only');
+	END;`
+
+	if _, err := m.DB.Exec(stmt); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Adds an entry to the audit log, the ID and Created fields are ignored
+func (m *AuditModel) Insert(e *AuditEntry) (int, error) {
+	stmt := `INSERT INTO audit_log (action, sound_id, sound, user_id, username, guild_id, old_value, new_value, created)
+	VALUES(?, ?, ?, ?, ?, ?, ?, ?, datetime('now'));`
+
+	res, err := m.DB.Exec(stmt, e.Action, e.SoundID, e.Sound, e.UserID, e.Username, e.GuildID, e.OldValue, e.NewValue)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(id), nil
+}
+
+// Gets the entries matching the options, newest first. Entries of a soundbite are
+// found by its current name as well as the names it had when each action was taken.
+func (m *AuditModel) List(opts AuditOptions) ([]*AuditEntry, error) {
+	stmt := `SELECT id, action, sound_id, sound, user_id, username, guild_id, old_value, new_value, created
+	FROM audit_log
+	WHERE (?1 = '' OR sound = ?1 OR sound_id IN (SELECT id FROM soundbites WHERE name = ?1))
+	AND (?2 = '' OR user_id = ?2)
+	ORDER BY id DESC LIMIT ?3;`
+
+	rows, err := m.DB.Query(stmt, opts.Sound, opts.UserID, opts.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []*AuditEntry{}
+	for rows.Next() {
+		var date string
+		e := &AuditEntry{}
+
+		err := rows.Scan(&e.ID, &e.Action, &e.SoundID, &e.Sound, &e.UserID, &e.Username, &e.GuildID,
+			&e.OldValue, &e.NewValue, &date)
+		if err != nil {
+			return nil, err
+		}
+
+		if e.Created, err = time.Parse(TIME_LAYOUT, date); err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, e)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
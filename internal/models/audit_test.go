@@ -0,0 +1,100 @@
+package models
+
+import (
+	"testing"
+
+	test "github.com/tweekes0/pal-bot/internal/testing"
+)
+
+func TestAudit(t *testing.T) {
+	t.Run("list entries", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		am := AuditModel{DB: m.DB}
+		initializeTestModels(t, &am)
+
+		id, _ := mockInsert(m, s1)
+		_, _ = mockInsert(m, s2)
+
+		entries := []*AuditEntry{
+			{Action: AuditCreate, SoundID: id, Sound: s1.Name, UserID: s1.UserID, Username: s1.Username},
+			{Action: AuditCreate, SoundID: 2, Sound: s2.Name, UserID: s2.UserID, Username: s2.Username},
+			{Action: AuditRename, SoundID: id, Sound: s1.Name, UserID: s2.UserID, Username: s2.Username,
+				OldValue: s1.Name, NewValue: "renamed"},
+		}
+
+		for _, e := range entries {
+			_, err := am.Insert(e)
+			test.AssertError(t, err, nil)
+		}
+
+		_ = m.UpdateName(s1.Name, "renamed")
+
+		tests := []struct {
+			name string
+			opts AuditOptions
+			want []AuditAction
+		}{
+			{"every entry", AuditOptions{Limit: 10}, []AuditAction{AuditRename, AuditCreate, AuditCreate}},
+			{"limited entries", AuditOptions{Limit: 1}, []AuditAction{AuditRename}},
+			{"entries by a user", AuditOptions{UserID: s2.UserID, Limit: 10}, []AuditAction{AuditRename, AuditCreate}},
+			{"entries of a soundbite by its old name", AuditOptions{Sound: s1.Name, Limit: 10}, []AuditAction{AuditRename, AuditCreate}},
+			{"entries of a soundbite by its new name", AuditOptions{Sound: "renamed", Limit: 10}, []AuditAction{AuditRename, AuditCreate}},
+			{"entries of an unknown soundbite", AuditOptions{Sound: s3.Name, Limit: 10}, []AuditAction{}},
+		}
+
+		for _, tc := range tests {
+			tc := tc
+			t.Run(tc.name, func(t *testing.T) {
+				got, err := am.List(tc.opts)
+				test.AssertError(t, err, nil)
+
+				actions := []AuditAction{}
+				for _, e := range got {
+					actions = append(actions, e.Action)
+				}
+
+				test.AssertType(t, actions, tc.want)
+			})
+		}
+	})
+
+	t.Run("entries outlive their soundbite", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		am := AuditModel{DB: m.DB}
+		initializeTestModels(t, &am)
+
+		id, _ := mockInsert(m, s1)
+		_, _ = am.Insert(&AuditEntry{Action: AuditPurge, SoundID: id, Sound: s1.Name})
+		_ = m.ForceDelete(s1.Name)
+
+		got, err := am.List(AuditOptions{Sound: s1.Name, Limit: 10})
+		test.AssertError(t, err, nil)
+		test.AssertType(t, len(got), 1)
+		test.AssertType(t, got[0].SoundID, id)
+	})
+
+	t.Run("entries cannot be changed", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		am := AuditModel{DB: m.DB}
+		initializeTestModels(t, &am)
+
+		_, _ = am.Insert(&AuditEntry{Action: AuditCreate, Sound: s1.Name})
+
+		if _, err := am.DB.Exec(`UPDATE audit_log SET sound = 'changed';`); err == nil {
+			t.Errorf("expected updating the audit log to fail")
+		}
+
+		if _, err := am.DB.Exec(`DELETE FROM audit_log;`); err == nil {
+			t.Errorf("expected deleting from the audit log to fail")
+		}
+
+		got, _ := am.List(AuditOptions{Sound: s1.Name, Limit: 10})
+		test.AssertType(t, len(got), 1)
+	})
+}
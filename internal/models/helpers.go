@@ -29,10 +29,21 @@ func modelsTestSetup(t *testing.T) (SoundbiteModel, func()) {
 	}
 
 	m := SoundbiteModel{DB: db}
-	m.Initialize()
+	initializeTestModels(t, &m)
 	teardown := func() {
 		os.Remove(f.Name())
 	}
 
 	return m, teardown
 }
+
+// Creates the tables of models that share a test's db, the test fails if any of them can't be created
+func initializeTestModels(t *testing.T, models ...interface{ Initialize() error }) {
+	t.Helper()
+
+	for _, m := range models {
+		if err := m.Initialize(); err != nil {
+			t.Fatalf("failed to initialize %T: %v", m, err)
+		}
+	}
+}
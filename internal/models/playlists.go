@@ -0,0 +1,240 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+)
+
+// Struct to present a record in the 'playlists' table and its soundbites in play order
+type Playlist struct {
+	ID       int
+	Name     string
+	Username string
+	UserID   string
+	Created  time.Time
+	Sounds   []*Soundbite
+}
+
+// Struct that holds the database connectivity for the 'playlists' and 'playlist_items' tables
+type PlaylistModel struct {
+	DB *sql.DB
+}
+
+// Initialize the 'playlists' and 'playlist_items' tables in the sqlite db, the 'soundbites'
+// table must already exist. Soundbites are removed from playlists when they are deleted.
+func (m *PlaylistModel) Initialize() error {
+	stmt := `CREATE TABLE IF NOT EXISTS playlists (
+		id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL,
+		username TEXT NOT NULL,
+		user_id TEXT NOT NULL,
+		created TEXT NOT NULL,
+		UNIQUE(name)
+	);
+	CREATE TABLE IF NOT EXISTS playlist_items (
+		playlist_id INTEGER NOT NULL REFERENCES playlists(id),
+		position INTEGER NOT NULL,
+		soundbite_id INTEGER NOT NULL REFERENCES soundbites(id),
+		UNIQUE(playlist_id, position)
+	);
+	CREATE TRIGGER IF NOT EXISTS playlist_items_playlist_delete AFTER DELETE ON playlists
+	BEGIN
+		DELETE FROM playlist_items WHERE playlist_id = OLD.id;
+	END;
+	CREATE TRIGGER IF NOT EXISTS playlist_items_soundbite_delete AFTER DELETE ON soundbites
+	BEGIN
+		DELETE FROM playlist_items WHERE soundbite_id = OLD.id;
+	END;`
+
+	if _, err := m.DB.Exec(stmt); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Creates a playlist of soundbites in the given order. Returns ErrDoesNotExist
+// if any of the soundbites does not exist.
+func (m *PlaylistModel) Insert(name, username, uid string, sounds []string) (int, error) {
+	tx, err := m.DB.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	stmt := `INSERT INTO playlists (name, username, user_id, created) VALUES(?, ?, ?, datetime('now'));`
+
+	res, err := tx.Exec(stmt, name, username, uid)
+	if err != nil {
+		if strings.Contains(err.Error(), "UNIQUE constraint") {
+			return 0, ErrUniqueConstraint
+		}
+
+		return 0, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	if err := insertItems(tx, int(id), sounds); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return int(id), nil
+}
+
+// Inserts the soundbites of a playlist in order
+func insertItems(tx *sql.Tx, id int, sounds []string) error {
+	stmt := `INSERT INTO playlist_items (playlist_id, position, soundbite_id)
+	SELECT ?, ?, id FROM soundbites WHERE name = ? AND deleted_at = '';`
+
+	for i, name := range sounds {
+		res, err := tx.Exec(stmt, id, i, name)
+		if err != nil {
+			return err
+		}
+
+		c, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if int(c) == 0 {
+			return ErrDoesNotExist
+		}
+	}
+
+	return nil
+}
+
+// Gets a playlist and its soundbites by name
+func (m *PlaylistModel) Get(name string) (*Playlist, error) {
+	var date string
+	p := &Playlist{}
+
+	stmt := `SELECT id, name, username, user_id, created FROM playlists WHERE name = ?;`
+	err := m.DB.QueryRow(stmt, name).Scan(&p.ID, &p.Name, &p.Username, &p.UserID, &date)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrDoesNotExist
+		}
+
+		return nil, err
+	}
+
+	p.Created, err = time.Parse(TIME_LAYOUT, date)
+	if err != nil {
+		return nil, err
+	}
+
+	stmt = `SELECT ` + prefixColumns("s", soundbiteColumns) + ` FROM playlist_items i
+	JOIN soundbites s ON s.id = i.soundbite_id WHERE i.playlist_id = ? AND s.deleted_at = ''
+	ORDER BY i.position;`
+
+	rows, err := m.DB.Query(stmt, p.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	p.Sounds = []*Soundbite{}
+	for rows.Next() {
+		s, err := scanSoundbite(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		p.Sounds = append(p.Sounds, s)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// Gets the names of every playlist in alphabetical order
+func (m *PlaylistModel) Names() ([]string, error) {
+	stmt := `SELECT name FROM playlists ORDER BY name;`
+
+	rows, err := m.DB.Query(stmt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	names := []string{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+
+		names = append(names, name)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return names, nil
+}
+
+// Replaces the soundbites of a playlist
+func (m *PlaylistModel) Update(name string, sounds []string) error {
+	tx, err := m.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var id int
+	err = tx.QueryRow(`SELECT id FROM playlists WHERE name = ?;`, name).Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrDoesNotExist
+		}
+
+		return err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM playlist_items WHERE playlist_id = ?;`, id); err != nil {
+		return err
+	}
+
+	if err := insertItems(tx, id, sounds); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Deletes a playlist regardless of which user created it
+func (m *PlaylistModel) Delete(name string) error {
+	stmt := `DELETE FROM playlists WHERE name = ?;`
+
+	res, err := m.DB.Exec(stmt, name)
+	if err != nil {
+		return err
+	}
+
+	c, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if int(c) == 0 {
+		return ErrDoesNotExist
+	}
+
+	return nil
+}
@@ -0,0 +1,153 @@
+package models
+
+import (
+	"testing"
+
+	test "github.com/tweekes0/pal-bot/internal/testing"
+)
+
+func TestPlaylistInsert(t *testing.T) {
+	t.Run("insert playlist", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		plm := PlaylistModel{DB: m.DB}
+		initializeTestModels(t, &plm)
+
+		_, _ = mockInsert(m, s1)
+		_, _ = mockInsert(m, s2)
+
+		id, err := plm.Insert("combo", s1.Username, s1.UserID, []string{s2.Name, s1.Name, s2.Name})
+		test.AssertError(t, err, nil)
+		test.AssertType(t, id, 1)
+
+		p, err := plm.Get("combo")
+		test.AssertError(t, err, nil)
+		test.AssertType(t, p.UserID, s1.UserID)
+		test.AssertType(t, listNames(p.Sounds), []string{s2.Name, s1.Name, s2.Name})
+		test.AssertType(t, p.Sounds[0].FilePath, s2.FilePath)
+	})
+
+	t.Run("insert duplicate playlist", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		plm := PlaylistModel{DB: m.DB}
+		initializeTestModels(t, &plm)
+
+		_, _ = mockInsert(m, s1)
+		_, _ = plm.Insert("combo", s1.Username, s1.UserID, []string{s1.Name})
+
+		_, err := plm.Insert("combo", s2.Username, s2.UserID, []string{s1.Name})
+		test.AssertError(t, err, ErrUniqueConstraint)
+	})
+
+	t.Run("insert playlist with non-existent soundbite", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		plm := PlaylistModel{DB: m.DB}
+		initializeTestModels(t, &plm)
+
+		_, _ = mockInsert(m, s1)
+
+		_, err := plm.Insert("combo", s1.Username, s1.UserID, []string{s1.Name, s2.Name})
+		test.AssertError(t, err, ErrDoesNotExist)
+
+		_, err = plm.Get("combo")
+		test.AssertError(t, err, ErrDoesNotExist)
+	})
+}
+
+func TestPlaylistUpdate(t *testing.T) {
+	t.Run("update playlist", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		plm := PlaylistModel{DB: m.DB}
+		initializeTestModels(t, &plm)
+
+		_, _ = mockInsert(m, s1)
+		_, _ = mockInsert(m, s2)
+		_, _ = mockInsert(m, s3)
+		_, _ = plm.Insert("combo", s1.Username, s1.UserID, []string{s1.Name, s2.Name})
+
+		err := plm.Update("combo", []string{s3.Name, s1.Name})
+		test.AssertError(t, err, nil)
+
+		p, _ := plm.Get("combo")
+		test.AssertType(t, listNames(p.Sounds), []string{s3.Name, s1.Name})
+	})
+
+	t.Run("failed update keeps playlist", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		plm := PlaylistModel{DB: m.DB}
+		initializeTestModels(t, &plm)
+
+		_, _ = mockInsert(m, s1)
+		_, _ = plm.Insert("combo", s1.Username, s1.UserID, []string{s1.Name})
+
+		err := plm.Update("combo", []string{s2.Name})
+		test.AssertError(t, err, ErrDoesNotExist)
+
+		p, _ := plm.Get("combo")
+		test.AssertType(t, listNames(p.Sounds), []string{s1.Name})
+	})
+
+	t.Run("update non-existent playlist", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		plm := PlaylistModel{DB: m.DB}
+		initializeTestModels(t, &plm)
+
+		_, _ = mockInsert(m, s1)
+
+		err := plm.Update("combo", []string{s1.Name})
+		test.AssertError(t, err, ErrDoesNotExist)
+	})
+}
+
+func TestPlaylistDelete(t *testing.T) {
+	t.Run("delete playlist", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		plm := PlaylistModel{DB: m.DB}
+		initializeTestModels(t, &plm)
+
+		_, _ = mockInsert(m, s1)
+		_, _ = plm.Insert("combo", s1.Username, s1.UserID, []string{s1.Name})
+		_, _ = plm.Insert("combo2", s1.Username, s1.UserID, []string{s1.Name})
+
+		err := plm.Delete("combo")
+		test.AssertError(t, err, nil)
+
+		names, err := plm.Names()
+		test.AssertError(t, err, nil)
+		test.AssertType(t, names, []string{"combo2"})
+
+		err = plm.Delete("combo")
+		test.AssertError(t, err, ErrDoesNotExist)
+	})
+
+	t.Run("deleted soundbites are removed from playlists", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		plm := PlaylistModel{DB: m.DB}
+		initializeTestModels(t, &plm)
+
+		_, _ = mockInsert(m, s1)
+		_, _ = mockInsert(m, s2)
+		_, _ = plm.Insert("combo", s1.Username, s1.UserID, []string{s1.Name, s2.Name, s1.Name})
+
+		_ = m.ForceDelete(s1.Name)
+
+		p, err := plm.Get("combo")
+		test.AssertError(t, err, nil)
+		test.AssertType(t, listNames(p.Sounds), []string{s2.Name})
+	})
+}
@@ -0,0 +1,167 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Struct that holds the database connectivity for the 'plays' table
+type PlayModel struct {
+	DB *sql.DB
+}
+
+// Initialize the 'plays' table in the sqlite db, the 'soundbites' table must already exist.
+// Plays are removed along with their soundbite.
+func (m *PlayModel) Initialize() error {
+	stmt := `CREATE TABLE IF NOT EXISTS plays (
+		id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+		soundbite_id INTEGER NOT NULL REFERENCES soundbites(id),
+		user_id TEXT NOT NULL,
+		guild_id TEXT NOT NULL,
+		played TEXT NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS plays_soundbite_id ON plays(soundbite_id);
+	CREATE TRIGGER IF NOT EXISTS plays_soundbite_delete AFTER DELETE ON soundbites
+	BEGIN
+		DELETE FROM plays WHERE soundbite_id = OLD.id;
+	END;`
+
+	if _, err := m.DB.Exec(stmt); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Records that a user played a soundbite in a guild
+func (m *PlayModel) Record(soundbiteID int, uid, guildID string) error {
+	stmt := `INSERT INTO plays (soundbite_id, user_id, guild_id, played) VALUES(?, ?, ?, datetime('now'));`
+
+	if _, err := m.DB.Exec(stmt, soundbiteID, uid, guildID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Struct for how many times a soundbite was played or a user played soundbites
+type PlayCount struct {
+	Name  string // The soundbite's name or the user's ID
+	Plays int
+}
+
+// Struct for the play statistics of a single soundbite
+type SoundbiteStats struct {
+	Plays      int
+	Players    int // The number of different users that played the soundbite
+	LastPlayed time.Time
+	TopPlayers []*PlayCount
+}
+
+// Struct for the play statistics of a single user
+type UserStats struct {
+	Plays     int
+	Sounds    int // The number of different soundbites the user played
+	Favorites []*PlayCount
+}
+
+// Gets the number of times a soundbite has been played in every guild
+func (m *PlayModel) Count(soundbiteID int) (int, error) {
+	var count int
+
+	stmt := `SELECT COUNT(*) FROM plays WHERE soundbite_id = ?;`
+	if err := m.DB.QueryRow(stmt, soundbiteID).Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// Gets the play statistics of a soundbite in a guild and the users that played it most
+func (m *PlayModel) SoundbiteStats(soundbiteID int, guildID string, limit int) (*SoundbiteStats, error) {
+	stats := &SoundbiteStats{}
+	var last sql.NullString
+
+	stmt := `SELECT COUNT(*), COUNT(DISTINCT user_id), MAX(played) FROM plays
+	WHERE soundbite_id = ? AND guild_id = ?;`
+
+	err := m.DB.QueryRow(stmt, soundbiteID, guildID).Scan(&stats.Plays, &stats.Players, &last)
+	if err != nil {
+		return nil, err
+	}
+
+	if last.Valid {
+		t, err := time.Parse(TIME_LAYOUT, last.String)
+		if err != nil {
+			return nil, err
+		}
+
+		stats.LastPlayed = t
+	}
+
+	stmt = `SELECT user_id, COUNT(*) AS c FROM plays WHERE soundbite_id = ? AND guild_id = ?
+	GROUP BY user_id ORDER BY c DESC, MAX(played) DESC LIMIT ?;`
+
+	stats.TopPlayers, err = m.queryPlayCounts(stmt, soundbiteID, guildID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// Gets the most played soundbites in a guild since a time, a zero time counts every play
+func (m *PlayModel) Top(guildID string, since time.Time, limit int) ([]*PlayCount, error) {
+	stmt := `SELECT s.name, COUNT(*) AS c FROM plays p JOIN soundbites s ON s.id = p.soundbite_id
+	WHERE p.guild_id = ? AND p.played >= ? AND s.deleted_at = ''
+	GROUP BY p.soundbite_id ORDER BY c DESC, s.name LIMIT ?;`
+
+	return m.queryPlayCounts(stmt, guildID, since.UTC().Format(TIME_LAYOUT), limit)
+}
+
+// Gets the play statistics of a user in a guild and the soundbites they played most
+func (m *PlayModel) UserStats(uid, guildID string, limit int) (*UserStats, error) {
+	stats := &UserStats{}
+
+	stmt := `SELECT COUNT(*), COUNT(DISTINCT soundbite_id) FROM plays WHERE user_id = ? AND guild_id = ?;`
+	err := m.DB.QueryRow(stmt, uid, guildID).Scan(&stats.Plays, &stats.Sounds)
+	if err != nil {
+		return nil, err
+	}
+
+	stmt = `SELECT s.name, COUNT(*) AS c FROM plays p JOIN soundbites s ON s.id = p.soundbite_id
+	WHERE p.user_id = ? AND p.guild_id = ? AND s.deleted_at = ''
+	GROUP BY p.soundbite_id ORDER BY c DESC, s.name LIMIT ?;`
+
+	stats.Favorites, err = m.queryPlayCounts(stmt, uid, guildID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// Runs a query that selects names and play counts
+func (m *PlayModel) queryPlayCounts(stmt string, args ...interface{}) ([]*PlayCount, error) {
+	rows, err := m.DB.Query(stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := []*PlayCount{}
+	for rows.Next() {
+		c := &PlayCount{}
+		if err := rows.Scan(&c.Name, &c.Plays); err != nil {
+			return nil, err
+		}
+
+		counts = append(counts, c)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
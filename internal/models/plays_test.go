@@ -0,0 +1,221 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	test "github.com/tweekes0/pal-bot/internal/testing"
+)
+
+func countPlays(t *testing.T, pm PlayModel) int {
+	var c int
+	if err := pm.DB.QueryRow(`SELECT COUNT(*) FROM plays;`).Scan(&c); err != nil {
+		t.Fatalf("failed to count plays: %v", err)
+	}
+
+	return c
+}
+
+// Moves every play of a soundbite back in time
+func agePlays(t *testing.T, pm PlayModel, id int, age time.Duration) {
+	played := time.Now().Add(-age).UTC().Format(TIME_LAYOUT)
+	if _, err := pm.DB.Exec(`UPDATE plays SET played = ? WHERE soundbite_id = ?;`, played, id); err != nil {
+		t.Fatalf("failed to age plays: %v", err)
+	}
+}
+
+func TestRecord(t *testing.T) {
+	t.Run("record plays", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		pm := PlayModel{DB: m.DB}
+		initializeTestModels(t, &TagModel{DB: m.DB}, &pm)
+
+		_, _ = mockInsert(m, s1)
+
+		err := pm.Record(s1.ID, s2.UserID, "guild")
+		test.AssertError(t, err, nil)
+
+		err = pm.Record(s1.ID, s3.UserID, "guild")
+		test.AssertError(t, err, nil)
+
+		test.AssertType(t, countPlays(t, pm), 2)
+	})
+
+	t.Run("plays are removed with their soundbite", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		pm := PlayModel{DB: m.DB}
+		initializeTestModels(t, &TagModel{DB: m.DB}, &pm)
+
+		_, _ = mockInsert(m, s1)
+		_, _ = mockInsert(m, s2)
+		_ = pm.Record(s1.ID, s1.UserID, "guild")
+		_ = pm.Record(s2.ID, s1.UserID, "guild")
+
+		err := m.ForceDelete(s1.Name)
+		test.AssertError(t, err, nil)
+		test.AssertType(t, countPlays(t, pm), 1)
+	})
+}
+
+func TestCount(t *testing.T) {
+	m, teardown := modelsTestSetup(t)
+	defer teardown()
+
+	pm := PlayModel{DB: m.DB}
+	initializeTestModels(t, &TagModel{DB: m.DB}, &pm)
+
+	_, _ = mockInsert(m, s1)
+	_, _ = mockInsert(m, s2)
+	_ = pm.Record(s1.ID, s1.UserID, "guild")
+	_ = pm.Record(s1.ID, s2.UserID, "other_guild")
+	_ = pm.Record(s2.ID, s1.UserID, "guild")
+
+	count, err := pm.Count(s1.ID)
+	test.AssertError(t, err, nil)
+	test.AssertType(t, count, 2)
+
+	count, err = pm.Count(s3.ID)
+	test.AssertError(t, err, nil)
+	test.AssertType(t, count, 0)
+}
+
+func TestSoundbiteStats(t *testing.T) {
+	t.Run("stats of a played soundbite", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		pm := PlayModel{DB: m.DB}
+		initializeTestModels(t, &TagModel{DB: m.DB}, &pm)
+
+		_, _ = mockInsert(m, s1)
+		_ = pm.Record(s1.ID, s1.UserID, "guild")
+		_ = pm.Record(s1.ID, s2.UserID, "guild")
+		_ = pm.Record(s1.ID, s2.UserID, "guild")
+		_ = pm.Record(s1.ID, s3.UserID, "other_guild")
+
+		stats, err := pm.SoundbiteStats(s1.ID, "guild", 5)
+		test.AssertError(t, err, nil)
+		test.AssertType(t, stats.Plays, 3)
+		test.AssertType(t, stats.Players, 2)
+		test.AssertType(t, stats.TopPlayers, []*PlayCount{
+			{Name: s2.UserID, Plays: 2},
+			{Name: s1.UserID, Plays: 1},
+		})
+
+		if time.Since(stats.LastPlayed) > time.Minute {
+			t.Fatalf("got: %v, expected a recent time", stats.LastPlayed)
+		}
+	})
+
+	t.Run("stats of an unplayed soundbite", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		pm := PlayModel{DB: m.DB}
+		initializeTestModels(t, &TagModel{DB: m.DB}, &pm)
+
+		_, _ = mockInsert(m, s1)
+
+		stats, err := pm.SoundbiteStats(s1.ID, "guild", 5)
+		test.AssertError(t, err, nil)
+		test.AssertType(t, stats.Plays, 0)
+		test.AssertType(t, stats.LastPlayed.IsZero(), true)
+		test.AssertType(t, stats.TopPlayers, []*PlayCount{})
+	})
+}
+
+func TestTop(t *testing.T) {
+	m, teardown := modelsTestSetup(t)
+	defer teardown()
+
+	pm := PlayModel{DB: m.DB}
+	initializeTestModels(t, &TagModel{DB: m.DB}, &pm)
+
+	_, _ = mockInsert(m, s1)
+	_, _ = mockInsert(m, s2)
+	_, _ = mockInsert(m, s3)
+
+	for i := 0; i < 3; i++ {
+		_ = pm.Record(s1.ID, s1.UserID, "guild")
+	}
+	for i := 0; i < 2; i++ {
+		_ = pm.Record(s2.ID, s1.UserID, "guild")
+	}
+	_ = pm.Record(s3.ID, s1.UserID, "guild")
+	_ = pm.Record(s3.ID, s1.UserID, "other_guild")
+	agePlays(t, pm, s1.ID, 48*time.Hour)
+
+	tt := []struct {
+		name     string
+		since    time.Time
+		limit    int
+		expected []*PlayCount
+	}{
+		{"top of all time", time.Time{}, 5, []*PlayCount{
+			{Name: s1.Name, Plays: 3},
+			{Name: s2.Name, Plays: 2},
+			{Name: s3.Name, Plays: 1},
+		}},
+		{"top with limit", time.Time{}, 1, []*PlayCount{
+			{Name: s1.Name, Plays: 3},
+		}},
+		{"top of the last day", time.Now().Add(-24 * time.Hour), 5, []*PlayCount{
+			{Name: s2.Name, Plays: 2},
+			{Name: s3.Name, Plays: 1},
+		}},
+		{"top of the future", time.Now().Add(time.Hour), 5, []*PlayCount{}},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			top, err := pm.Top("guild", tc.since, tc.limit)
+			test.AssertError(t, err, nil)
+			test.AssertType(t, top, tc.expected)
+		})
+	}
+}
+
+func TestUserStats(t *testing.T) {
+	t.Run("stats of a user", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		pm := PlayModel{DB: m.DB}
+		initializeTestModels(t, &TagModel{DB: m.DB}, &pm)
+
+		_, _ = mockInsert(m, s1)
+		_, _ = mockInsert(m, s2)
+		_ = pm.Record(s1.ID, s1.UserID, "guild")
+		_ = pm.Record(s2.ID, s1.UserID, "guild")
+		_ = pm.Record(s2.ID, s1.UserID, "guild")
+		_ = pm.Record(s2.ID, s2.UserID, "guild")
+		_ = pm.Record(s1.ID, s1.UserID, "other_guild")
+
+		stats, err := pm.UserStats(s1.UserID, "guild", 5)
+		test.AssertError(t, err, nil)
+		test.AssertType(t, stats.Plays, 3)
+		test.AssertType(t, stats.Sounds, 2)
+		test.AssertType(t, stats.Favorites, []*PlayCount{
+			{Name: s2.Name, Plays: 2},
+			{Name: s1.Name, Plays: 1},
+		})
+	})
+
+	t.Run("stats of a user without plays", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		pm := PlayModel{DB: m.DB}
+		initializeTestModels(t, &TagModel{DB: m.DB}, &pm)
+
+		stats, err := pm.UserStats(s1.UserID, "guild", 5)
+		test.AssertError(t, err, nil)
+		test.AssertType(t, stats.Plays, 0)
+		test.AssertType(t, stats.Favorites, []*PlayCount{})
+	})
+}
@@ -0,0 +1,206 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// Struct to present a record in the 'schedules' table along with the name of its soundbite
+type Schedule struct {
+	ID         int
+	SoundName  string
+	GuildID    string
+	ChannelID  string // The VoiceChannel the soundbite plays in
+	UserID     string
+	Username   string
+	Recurrence string // How the schedule repeats such as 'every friday 17:00', empty if it only runs once
+	NextRun    time.Time
+	Created    time.Time
+}
+
+// Struct that holds the database connectivity for the 'schedules' table
+type ScheduleModel struct {
+	DB *sql.DB
+}
+
+// Columns of the 'schedules' table in the order scanSchedule reads them
+const scheduleColumns = `sc.id, s.name, sc.guild_id, sc.channel_id, sc.user_id, sc.username,
+	sc.recurrence, sc.next_run, sc.created`
+
+// Initialize the 'schedules' table in the sqlite db, the 'soundbites' table must already
+// exist. Schedules are removed along with their soundbite.
+func (m *ScheduleModel) Initialize() error {
+	stmt := `CREATE TABLE IF NOT EXISTS schedules (
+		id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+		soundbite_id INTEGER NOT NULL REFERENCES soundbites(id),
+		guild_id TEXT NOT NULL,
+		channel_id TEXT NOT NULL,
+		user_id TEXT NOT NULL,
+		username TEXT NOT NULL,
+		recurrence TEXT NOT NULL DEFAULT '',
+		next_run TEXT NOT NULL,
+		created TEXT NOT NULL
+	);
+	CREATE TRIGGER IF NOT EXISTS schedules_soundbite_delete AFTER DELETE ON soundbites
+	BEGIN
+		DELETE FROM schedules WHERE soundbite_id = OLD.id;
+	END;`
+
+	if _, err := m.DB.Exec(stmt); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Creates a schedule for the soundbite named in sc.SoundName. Returns ErrDoesNotExist
+// if the soundbite does not exist.
+func (m *ScheduleModel) Insert(sc *Schedule) (int, error) {
+	stmt := `INSERT INTO schedules (soundbite_id, guild_id, channel_id, user_id, username, recurrence, next_run, created)
+	SELECT id, ?, ?, ?, ?, ?, ?, datetime('now') FROM soundbites WHERE name = ? AND deleted_at = '';`
+
+	res, err := m.DB.Exec(stmt, sc.GuildID, sc.ChannelID, sc.UserID, sc.Username, sc.Recurrence,
+		sc.NextRun.UTC().Format(TIME_LAYOUT), sc.SoundName)
+	if err != nil {
+		return 0, err
+	}
+
+	c, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	if int(c) == 0 {
+		return 0, ErrDoesNotExist
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(id), nil
+}
+
+// Reads a schedule from a row selected with scheduleColumns
+func scanSchedule(row scanner) (*Schedule, error) {
+	var next, created string
+	sc := &Schedule{}
+
+	err := row.Scan(&sc.ID, &sc.SoundName, &sc.GuildID, &sc.ChannelID, &sc.UserID, &sc.Username,
+		&sc.Recurrence, &next, &created)
+	if err != nil {
+		return nil, err
+	}
+
+	if sc.NextRun, err = time.Parse(TIME_LAYOUT, next); err != nil {
+		return nil, err
+	}
+
+	if sc.Created, err = time.Parse(TIME_LAYOUT, created); err != nil {
+		return nil, err
+	}
+
+	return sc, nil
+}
+
+// Gets a schedule by ID
+func (m *ScheduleModel) Get(id int) (*Schedule, error) {
+	stmt := `SELECT ` + scheduleColumns + ` FROM schedules sc
+	JOIN soundbites s ON s.id = sc.soundbite_id WHERE sc.id = ?;`
+
+	sc, err := scanSchedule(m.DB.QueryRow(stmt, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrDoesNotExist
+		}
+
+		return nil, err
+	}
+
+	return sc, nil
+}
+
+// Gets every schedule of a guild, or of every guild if guildID is empty, in the order they run
+func (m *ScheduleModel) List(guildID string) ([]*Schedule, error) {
+	stmt := `SELECT ` + scheduleColumns + ` FROM schedules sc
+	JOIN soundbites s ON s.id = sc.soundbite_id
+	WHERE ? = '' OR sc.guild_id = ? ORDER BY sc.next_run, sc.id;`
+
+	rows, err := m.DB.Query(stmt, guildID, guildID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	schedules := []*Schedule{}
+	for rows.Next() {
+		sc, err := scanSchedule(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		schedules = append(schedules, sc)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return schedules, nil
+}
+
+// Gets the number of schedules a user has created
+func (m *ScheduleModel) Count(uid string) (int, error) {
+	var count int
+
+	stmt := `SELECT COUNT(*) FROM schedules WHERE user_id = ?;`
+	if err := m.DB.QueryRow(stmt, uid).Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// Sets when a recurring schedule runs next
+func (m *ScheduleModel) UpdateNextRun(id int, next time.Time) error {
+	stmt := `UPDATE schedules SET next_run = ? WHERE id = ?;`
+
+	res, err := m.DB.Exec(stmt, next.UTC().Format(TIME_LAYOUT), id)
+	if err != nil {
+		return err
+	}
+
+	c, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if int(c) == 0 {
+		return ErrDoesNotExist
+	}
+
+	return nil
+}
+
+// Deletes a schedule
+func (m *ScheduleModel) Delete(id int) error {
+	stmt := `DELETE FROM schedules WHERE id = ?;`
+
+	res, err := m.DB.Exec(stmt, id)
+	if err != nil {
+		return err
+	}
+
+	c, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if int(c) == 0 {
+		return ErrDoesNotExist
+	}
+
+	return nil
+}
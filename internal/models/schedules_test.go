@@ -0,0 +1,144 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	test "github.com/tweekes0/pal-bot/internal/testing"
+)
+
+// Creates a schedule of a soundbite in a guild that runs after the given delay
+func mockSchedule(name, guildID string, in time.Duration) *Schedule {
+	return &Schedule{
+		SoundName: name,
+		GuildID:   guildID,
+		ChannelID: "voice_channel",
+		UserID:    s1.UserID,
+		Username:  s1.Username,
+		NextRun:   time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC).Add(in),
+	}
+}
+
+func TestSchedules(t *testing.T) {
+	t.Run("insert and get schedule", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		scm := ScheduleModel{DB: m.DB}
+		initializeTestModels(t, &scm)
+
+		_, _ = mockInsert(m, s1)
+		sc := mockSchedule(s1.Name, "guild", time.Hour)
+		sc.Recurrence = "every friday 17:00"
+
+		id, err := scm.Insert(sc)
+		test.AssertError(t, err, nil)
+
+		got, err := scm.Get(id)
+		test.AssertError(t, err, nil)
+		test.AssertType(t, got.ID, id)
+		test.AssertType(t, got.SoundName, s1.Name)
+		test.AssertType(t, got.ChannelID, sc.ChannelID)
+		test.AssertType(t, got.Recurrence, sc.Recurrence)
+		test.AssertType(t, got.NextRun, sc.NextRun)
+
+		_, err = scm.Get(id + 1)
+		test.AssertError(t, err, ErrDoesNotExist)
+	})
+
+	t.Run("insert schedule of non-existent soundbite", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		scm := ScheduleModel{DB: m.DB}
+		initializeTestModels(t, &scm)
+
+		_, err := scm.Insert(mockSchedule(s1.Name, "guild", time.Hour))
+		test.AssertError(t, err, ErrDoesNotExist)
+	})
+
+	t.Run("list schedules in the order they run", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		scm := ScheduleModel{DB: m.DB}
+		initializeTestModels(t, &scm)
+
+		_, _ = mockInsert(m, s1)
+		_, _ = mockInsert(m, s2)
+		_, _ = scm.Insert(mockSchedule(s1.Name, "guild", 2*time.Hour))
+		_, _ = scm.Insert(mockSchedule(s2.Name, "guild", time.Hour))
+		_, _ = scm.Insert(mockSchedule(s1.Name, "other_guild", time.Minute))
+
+		schedules, err := scm.List("guild")
+		test.AssertError(t, err, nil)
+		test.AssertType(t, len(schedules), 2)
+		test.AssertType(t, schedules[0].SoundName, s2.Name)
+		test.AssertType(t, schedules[1].SoundName, s1.Name)
+
+		schedules, err = scm.List("")
+		test.AssertError(t, err, nil)
+		test.AssertType(t, len(schedules), 3)
+		test.AssertType(t, schedules[0].GuildID, "other_guild")
+
+		count, err := scm.Count(s1.UserID)
+		test.AssertError(t, err, nil)
+		test.AssertType(t, count, 3)
+	})
+
+	t.Run("update next run", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		scm := ScheduleModel{DB: m.DB}
+		initializeTestModels(t, &scm)
+
+		_, _ = mockInsert(m, s1)
+		id, _ := scm.Insert(mockSchedule(s1.Name, "guild", time.Hour))
+
+		next := time.Date(2022, 6, 8, 17, 0, 0, 0, time.UTC)
+		err := scm.UpdateNextRun(id, next)
+		test.AssertError(t, err, nil)
+
+		sc, err := scm.Get(id)
+		test.AssertError(t, err, nil)
+		test.AssertType(t, sc.NextRun, next)
+
+		err = scm.UpdateNextRun(id+1, next)
+		test.AssertError(t, err, ErrDoesNotExist)
+	})
+
+	t.Run("delete schedule", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		scm := ScheduleModel{DB: m.DB}
+		initializeTestModels(t, &scm)
+
+		_, _ = mockInsert(m, s1)
+		id, _ := scm.Insert(mockSchedule(s1.Name, "guild", time.Hour))
+
+		err := scm.Delete(id)
+		test.AssertError(t, err, nil)
+
+		err = scm.Delete(id)
+		test.AssertError(t, err, ErrDoesNotExist)
+	})
+
+	t.Run("deleting soundbite deletes its schedules", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		scm := ScheduleModel{DB: m.DB}
+		initializeTestModels(t, &scm)
+
+		_, _ = mockInsert(m, s1)
+		id, _ := scm.Insert(mockSchedule(s1.Name, "guild", time.Hour))
+
+		err := m.ForceDelete(s1.Name)
+		test.AssertError(t, err, nil)
+
+		_, err = scm.Get(id)
+		test.AssertError(t, err, ErrDoesNotExist)
+	})
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// Adds a column to a table if the table does not have it yet. Used to bring
+// tables created by older versions of the bot up to date.
+func addColumn(db *sql.DB, table, column, definition string) error {
+	exists, err := hasColumn(db, table, column)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		return nil
+	}
+
+	stmt := fmt.Sprintf(`ALTER TABLE %v ADD COLUMN %v %v;`, table, column, definition)
+	if _, err := db.Exec(stmt); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Checks whether a table has a column
+func hasColumn(db *sql.DB, table, column string) (bool, error) {
+	var exists bool
+
+	stmt := `SELECT EXISTS(SELECT 1 FROM pragma_table_info(?) WHERE name = ?);`
+	err := db.QueryRow(stmt, table, column).Scan(&exists)
+
+	return exists, err
+}
+
+// Qualifies each column in a comma separated list with a table name or alias
+func prefixColumns(table, columns string) string {
+	fields := strings.Split(columns, ",")
+	for i, f := range fields {
+		fields[i] = table + "." + strings.TrimSpace(f)
+	}
+
+	return strings.Join(fields, ", ")
+}
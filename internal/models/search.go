@@ -0,0 +1,250 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/tweekes0/pal-bot/internal/fuzzy"
+)
+
+const (
+	// FTS5 trigram queries need at least this many characters
+	MIN_TRIGRAM_LENGTH = 3
+)
+
+// Struct that holds the database connectivity for searching soundbites
+type SearchModel struct {
+	DB  *sql.DB
+	fts bool // Whether the sqlite driver was built with FTS5, searches fall back to LIKE without it
+}
+
+// Drops the triggers that keep the 'soundbite_search' index up to date
+const dropSearchTriggers = `DROP TRIGGER IF EXISTS soundbite_search_insert;
+DROP TRIGGER IF EXISTS soundbite_search_update;
+DROP TRIGGER IF EXISTS soundbite_search_delete;
+DROP TRIGGER IF EXISTS soundbite_search_tag_insert;
+DROP TRIGGER IF EXISTS soundbite_search_tag_delete;`
+
+// Initialize the 'soundbite_search' FTS5 index of soundbite names, creators, tags and source titles.
+// The 'soundbites' and 'tags' tables must already exist. The index is rebuilt every
+// time so it always matches its tables. Without FTS5 searches use the tables directly and
+// the triggers left by a build with FTS5 are dropped, they would fail on every write.
+func (m *SearchModel) Initialize() error {
+	stmt := `DROP TABLE IF EXISTS soundbite_search;
+	CREATE VIRTUAL TABLE soundbite_search USING fts5(name, username, tags, title, tokenize = 'trigram');`
+
+	if _, err := m.DB.Exec(stmt); err != nil {
+		if strings.Contains(err.Error(), "no such module") {
+			m.fts = false
+			_, err = m.DB.Exec(dropSearchTriggers)
+			return err
+		}
+
+		return err
+	}
+
+	m.fts = true
+
+	stmt = `INSERT INTO soundbite_search (rowid, name, username, tags, title)
+	SELECT id, name, username, ` + searchTags("id") + `, source_title FROM soundbites;
+
+	DROP TRIGGER IF EXISTS soundbite_search_insert;
+	CREATE TRIGGER soundbite_search_insert AFTER INSERT ON soundbites
+	BEGIN
+		INSERT INTO soundbite_search (rowid, name, username, tags, title)
+		VALUES(NEW.id, NEW.name, NEW.username, ` + searchTags("NEW.id") + `, NEW.source_title);
+	END;
+
+	DROP TRIGGER IF EXISTS soundbite_search_update;
+	CREATE TRIGGER soundbite_search_update AFTER UPDATE ON soundbites
+	BEGIN
+		UPDATE soundbite_search SET name = NEW.name, username = NEW.username, title = NEW.source_title
+		WHERE rowid = NEW.id;
+	END;
+
+	DROP TRIGGER IF EXISTS soundbite_search_delete;
+	CREATE TRIGGER soundbite_search_delete AFTER DELETE ON soundbites
+	BEGIN
+		DELETE FROM soundbite_search WHERE rowid = OLD.id;
+	END;
+
+	DROP TRIGGER IF EXISTS soundbite_search_tag_insert;
+	CREATE TRIGGER soundbite_search_tag_insert AFTER INSERT ON tags
+	BEGIN
+		UPDATE soundbite_search SET tags = ` + searchTags("NEW.soundbite_id") + ` WHERE rowid = NEW.soundbite_id;
+	END;
+
+	DROP TRIGGER IF EXISTS soundbite_search_tag_delete;
+	CREATE TRIGGER soundbite_search_tag_delete AFTER DELETE ON tags
+	BEGIN
+		UPDATE soundbite_search SET tags = ` + searchTags("OLD.soundbite_id") + ` WHERE rowid = OLD.soundbite_id;
+	END;`
+
+	if _, err := m.DB.Exec(stmt); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Creates a subquery of a soundbite's tags separated by spaces
+func searchTags(id string) string {
+	return fmt.Sprintf(`COALESCE((SELECT group_concat(tag, ' ') FROM tags WHERE soundbite_id = %v), '')`, id)
+}
+
+// Searches soundbite names, creators, tags and source titles for the query and returns up
+// to limit soundbites that are not in the trash. Substring matches come first, followed by names, creators and tags
+// that are within a few typos of the query.
+func (m *SearchModel) Search(query string, limit int) ([]*Soundbite, error) {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return []*Soundbite{}, nil
+	}
+
+	var ids []int
+	var err error
+
+	if m.fts && utf8.RuneCountInString(query) >= MIN_TRIGRAM_LENGTH {
+		ids, err = m.matchFTS(query, limit)
+	} else {
+		ids, err = m.matchLike(query, limit)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ids) < limit {
+		fuzzyIDs, err := m.matchFuzzy(query)
+		if err != nil {
+			return nil, err
+		}
+
+		ids = appendUnique(ids, fuzzyIDs, limit)
+	}
+
+	soundbites := []*Soundbite{}
+	stmt := `SELECT ` + soundbiteColumns + ` FROM soundbites WHERE id = ?;`
+
+	for _, id := range ids {
+		s, err := scanSoundbite(m.DB.QueryRow(stmt, id))
+		if err != nil {
+			return nil, err
+		}
+
+		soundbites = append(soundbites, s)
+	}
+
+	return soundbites, nil
+}
+
+// Finds soundbites containing the query with the FTS5 index, best matches first
+func (m *SearchModel) matchFTS(query string, limit int) ([]int, error) {
+	// quote the query so it is matched as a single phrase
+	phrase := `"` + strings.ReplaceAll(query, `"`, `""`) + `"`
+	stmt := `SELECT rowid FROM soundbite_search WHERE soundbite_search MATCH ?
+	AND rowid IN (SELECT id FROM soundbites WHERE deleted_at = '') ORDER BY rank LIMIT ?;`
+
+	return m.queryIDs(stmt, phrase, limit)
+}
+
+// Finds soundbites containing the query without the FTS5 index, shortest names first
+func (m *SearchModel) matchLike(query string, limit int) ([]int, error) {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	pattern := "%" + replacer.Replace(query) + "%"
+
+	stmt := `SELECT id FROM soundbites WHERE deleted_at = ''
+	AND (name LIKE ?1 ESCAPE '\' OR username LIKE ?1 ESCAPE '\' OR source_title LIKE ?1 ESCAPE '\'
+	OR id IN (SELECT soundbite_id FROM tags WHERE tag LIKE ?1 ESCAPE '\'))
+	ORDER BY length(name), name LIMIT ?2;`
+
+	return m.queryIDs(stmt, pattern, limit)
+}
+
+// Finds soundbites whose name, creator or tags are within a few typos of the query, closest first
+func (m *SearchModel) matchFuzzy(query string) ([]int, error) {
+	stmt := `SELECT id, name FROM soundbites WHERE deleted_at = ''
+	UNION ALL SELECT id, username FROM soundbites WHERE deleted_at = ''
+	UNION ALL SELECT soundbite_id, tag FROM tags
+	WHERE soundbite_id IN (SELECT id FROM soundbites WHERE deleted_at = '');`
+
+	rows, err := m.DB.Query(stmt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	values := make(map[string][]int)
+	candidates := []string{}
+
+	for rows.Next() {
+		var id int
+		var value string
+		if err := rows.Scan(&id, &value); err != nil {
+			return nil, err
+		}
+
+		value = strings.ToLower(value)
+		values[value] = append(values[value], id)
+		candidates = append(candidates, value)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	ids := []int{}
+	for _, match := range fuzzy.Closest(query, candidates, fuzzy.MaxDistance(query), len(candidates)) {
+		ids = appendUnique(ids, values[match.Value], len(candidates))
+	}
+
+	return ids, nil
+}
+
+// Runs a query that selects soundbite ids
+func (m *SearchModel) queryIDs(stmt string, args ...interface{}) ([]int, error) {
+	rows, err := m.DB.Query(stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []int{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// Appends the ids that are not in dst yet until dst holds limit ids
+func appendUnique(dst, ids []int, limit int) []int {
+	seen := make(map[int]bool)
+	for _, id := range dst {
+		seen[id] = true
+	}
+
+	for _, id := range ids {
+		if len(dst) >= limit {
+			break
+		}
+
+		if !seen[id] {
+			seen[id] = true
+			dst = append(dst, id)
+		}
+	}
+
+	return dst
+}
@@ -0,0 +1,198 @@
+package models
+
+import (
+	"testing"
+
+	test "github.com/tweekes0/pal-bot/internal/testing"
+)
+
+func searchNames(t *testing.T, sm SearchModel, query string, limit int) []string {
+	sounds, err := sm.Search(query, limit)
+	test.AssertError(t, err, nil)
+
+	return listNames(sounds)
+}
+
+func TestSearch(t *testing.T) {
+	tt := []struct {
+		description string
+		query       string
+		limit       int
+		expected    []string
+	}{
+		{
+			description: "search by exact name",
+			query:       "airhorn",
+			limit:       5,
+			expected:    []string{"airhorn", "airhorns"},
+		},
+		{
+			description: "search by part of name",
+			query:       "horn",
+			limit:       5,
+			expected:    []string{"airhorn", "airhorns"},
+		},
+		{
+			description: "search by creator",
+			query:       "pal",
+			limit:       5,
+			expected:    []string{"bruh"},
+		},
+		{
+			description: "search by tag",
+			query:       "pokemon",
+			limit:       5,
+			expected:    []string{"jigglypuff"},
+		},
+		{
+			description: "search by source title",
+			query:       "lullaby",
+			limit:       5,
+			expected:    []string{"jigglypuff"},
+		},
+		{
+			description: "search with typo",
+			query:       "jiglypuf",
+			limit:       5,
+			expected:    []string{"jigglypuff"},
+		},
+		{
+			description: "search with short query",
+			query:       "br",
+			limit:       5,
+			expected:    []string{"bruh"},
+		},
+		{
+			description: "search with uppercase query",
+			query:       "BRUH",
+			limit:       5,
+			expected:    []string{"bruh"},
+		},
+		{
+			description: "search limited results",
+			query:       "horn",
+			limit:       1,
+			expected:    []string{"airhorn"},
+		},
+		{
+			description: "search without matches",
+			query:       "rimshot",
+			limit:       5,
+			expected:    []string{},
+		},
+		{
+			description: "search with quotes and wildcards",
+			query:       `"%_`,
+			limit:       5,
+			expected:    []string{},
+		},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			m, teardown := modelsTestSetup(t)
+			defer teardown()
+
+			tm := TagModel{DB: m.DB}
+			sm := SearchModel{DB: m.DB}
+			initializeTestModels(t, &tm, &sm)
+
+			_, _ = m.Insert(&Soundbite{Name: "airhorn", Username: "test_username_1", UserID: "111111", FilePath: "/path/to/file/1", FileHash: "sha256:111111"})
+			_, _ = m.Insert(&Soundbite{Name: "airhorns", Username: "test_username_1", UserID: "111111", FilePath: "/path/to/file/2", FileHash: "sha256:222222"})
+			_, _ = m.Insert(&Soundbite{Name: "bruh", Username: "pal", UserID: "222222", FilePath: "/path/to/file/3", FileHash: "sha256:333333"})
+			_, _ = m.Insert(&Soundbite{Name: "jigglypuff", Username: "test_username_2", UserID: "333333", FilePath: "/path/to/file/4", FileHash: "sha256:444444", SourceTitle: "Jigglypuff Sings A Lullaby"})
+			_ = tm.Add("jigglypuff", "pokemon")
+
+			test.AssertType(t, searchNames(t, sm, tc.query, tc.limit), tc.expected)
+		})
+	}
+}
+
+func TestSearchIndex(t *testing.T) {
+	t.Run("search follows renames, tag changes and deletes", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		tm := TagModel{DB: m.DB}
+		sm := SearchModel{DB: m.DB}
+		initializeTestModels(t, &tm, &sm)
+
+		_, _ = mockInsert(m, s1)
+		_, _ = mockInsert(m, s2)
+
+		_ = m.UpdateName(s1.Name, "rimshot")
+		test.AssertType(t, searchNames(t, sm, "rimshot", 5), []string{"rimshot"})
+
+		_ = tm.Add(s2.Name, "drums")
+		test.AssertType(t, searchNames(t, sm, "drums", 5), []string{s2.Name})
+
+		_ = tm.Remove(s2.Name, "drums")
+		test.AssertType(t, searchNames(t, sm, "drums", 5), []string{})
+
+		_ = m.ForceDelete("rimshot")
+		test.AssertType(t, searchNames(t, sm, "rimshot", 5), []string{})
+	})
+
+	t.Run("search soundbites created before the index", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		tm := TagModel{DB: m.DB}
+		initializeTestModels(t, &tm)
+
+		_, _ = mockInsert(m, s1)
+		_ = tm.Add(s1.Name, "drums")
+
+		sm := SearchModel{DB: m.DB}
+		err := sm.Initialize()
+		test.AssertError(t, err, nil)
+
+		test.AssertType(t, searchNames(t, sm, "drums", 5), []string{s1.Name})
+
+		err = sm.Initialize()
+		test.AssertError(t, err, nil)
+
+		test.AssertType(t, searchNames(t, sm, "drums", 5), []string{s1.Name})
+	})
+	t.Run("writes work with triggers left by a build with FTS5", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		tm := TagModel{DB: m.DB}
+		initializeTestModels(t, &tm)
+
+		// without FTS5 the index these triggers write to does not exist
+		stmt := `CREATE TRIGGER IF NOT EXISTS soundbite_search_insert AFTER INSERT ON soundbites
+		BEGIN INSERT INTO soundbite_search (rowid) VALUES(NEW.id); END;
+		CREATE TRIGGER IF NOT EXISTS soundbite_search_update AFTER UPDATE ON soundbites
+		BEGIN UPDATE soundbite_search SET name = NEW.name WHERE rowid = NEW.id; END;
+		CREATE TRIGGER IF NOT EXISTS soundbite_search_delete AFTER DELETE ON soundbites
+		BEGIN DELETE FROM soundbite_search WHERE rowid = OLD.id; END;
+		CREATE TRIGGER IF NOT EXISTS soundbite_search_tag_insert AFTER INSERT ON tags
+		BEGIN UPDATE soundbite_search SET tags = '' WHERE rowid = NEW.soundbite_id; END;
+		CREATE TRIGGER IF NOT EXISTS soundbite_search_tag_delete AFTER DELETE ON tags
+		BEGIN UPDATE soundbite_search SET tags = '' WHERE rowid = OLD.soundbite_id; END;`
+
+		_, err := m.DB.Exec(stmt)
+		test.AssertError(t, err, nil)
+
+		sm := SearchModel{DB: m.DB}
+		initializeTestModels(t, &sm)
+
+		_, err = mockInsert(m, s1)
+		test.AssertError(t, err, nil)
+
+		err = tm.Add(s1.Name, "drums")
+		test.AssertError(t, err, nil)
+
+		err = tm.Remove(s1.Name, "drums")
+		test.AssertError(t, err, nil)
+
+		err = m.UpdateName(s1.Name, "rimshot")
+		test.AssertError(t, err, nil)
+
+		err = m.ForceDelete("rimshot")
+		test.AssertError(t, err, nil)
+	})
+}
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+)
+
+// Struct to present a record in the 'guild_settings' table, guilds without
+// a record use the defaults from DefaultGuildSettings
+type GuildSettings struct {
+	GuildID        string
+	ThemesEnabled  bool   // Whether members' intro and outro sounds are played
+	PlaybackPolicy string // What happens to sounds played while another is playing
+}
+
+// Gets the settings of a guild that has not changed any
+func DefaultGuildSettings(guildID string) *GuildSettings {
+	return &GuildSettings{
+		GuildID:        guildID,
+		ThemesEnabled:  true,
+		PlaybackPolicy: "queue",
+	}
+}
+
+// Struct that holds the database connectivity for the 'guild_settings' table
+type SettingsModel struct {
+	DB *sql.DB
+}
+
+// Initialize the 'guild_settings' table in the sqlite db
+func (m *SettingsModel) Initialize() error {
+	stmt := `CREATE TABLE IF NOT EXISTS guild_settings (
+		guild_id TEXT NOT NULL PRIMARY KEY,
+		themes_enabled INTEGER NOT NULL DEFAULT 1
+	);`
+
+	if _, err := m.DB.Exec(stmt); err != nil {
+		return err
+	}
+
+	return addColumn(m.DB, "guild_settings", "playback_policy", "TEXT NOT NULL DEFAULT 'queue'")
+}
+
+// Gets the settings of a guild
+func (m *SettingsModel) Get(guildID string) (*GuildSettings, error) {
+	gs := &GuildSettings{}
+
+	stmt := `SELECT guild_id, themes_enabled, playback_policy FROM guild_settings WHERE guild_id = ?;`
+	err := m.DB.QueryRow(stmt, guildID).Scan(&gs.GuildID, &gs.ThemesEnabled, &gs.PlaybackPolicy)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return DefaultGuildSettings(guildID), nil
+		}
+
+		return nil, err
+	}
+
+	return gs, nil
+}
+
+// Saves the settings of a guild, replacing any it had before
+func (m *SettingsModel) Save(gs *GuildSettings) error {
+	stmt := `INSERT INTO guild_settings (guild_id, themes_enabled, playback_policy) VALUES(?, ?, ?)
+	ON CONFLICT(guild_id) DO UPDATE SET themes_enabled = excluded.themes_enabled,
+	playback_policy = excluded.playback_policy;`
+
+	if _, err := m.DB.Exec(stmt, gs.GuildID, gs.ThemesEnabled, gs.PlaybackPolicy); err != nil {
+		return err
+	}
+
+	return nil
+}
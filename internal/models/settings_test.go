@@ -0,0 +1,73 @@
+package models
+
+import (
+	"testing"
+
+	test "github.com/tweekes0/pal-bot/internal/testing"
+)
+
+func TestSettings(t *testing.T) {
+	t.Run("get settings of new guild", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		gm := SettingsModel{DB: m.DB}
+		initializeTestModels(t, &gm)
+
+		gs, err := gm.Get("guild")
+		test.AssertError(t, err, nil)
+		test.AssertType(t, gs, DefaultGuildSettings("guild"))
+	})
+
+	t.Run("save and update settings", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		gm := SettingsModel{DB: m.DB}
+		initializeTestModels(t, &gm)
+
+		err := gm.Save(&GuildSettings{GuildID: "guild", ThemesEnabled: false, PlaybackPolicy: "mix"})
+		test.AssertError(t, err, nil)
+
+		gs, err := gm.Get("guild")
+		test.AssertError(t, err, nil)
+		test.AssertType(t, gs.ThemesEnabled, false)
+		test.AssertType(t, gs.PlaybackPolicy, "mix")
+
+		err = gm.Save(&GuildSettings{GuildID: "guild", ThemesEnabled: true, PlaybackPolicy: "interrupt"})
+		test.AssertError(t, err, nil)
+
+		gs, err = gm.Get("guild")
+		test.AssertError(t, err, nil)
+		test.AssertType(t, gs.ThemesEnabled, true)
+		test.AssertType(t, gs.PlaybackPolicy, "interrupt")
+
+		gs, err = gm.Get("other_guild")
+		test.AssertError(t, err, nil)
+		test.AssertType(t, gs, DefaultGuildSettings("other_guild"))
+	})
+
+	t.Run("initialize table created without playback policy", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		gm := SettingsModel{DB: m.DB}
+		initializeTestModels(t, &gm)
+
+		_, err := gm.DB.Exec(`DROP TABLE guild_settings;
+		CREATE TABLE guild_settings (
+			guild_id TEXT NOT NULL PRIMARY KEY,
+			themes_enabled INTEGER NOT NULL DEFAULT 1
+		);
+		INSERT INTO guild_settings (guild_id, themes_enabled) VALUES('guild', 0);`)
+		test.AssertError(t, err, nil)
+
+		err = gm.Initialize()
+		test.AssertError(t, err, nil)
+
+		gs, err := gm.Get("guild")
+		test.AssertError(t, err, nil)
+		test.AssertType(t, gs.ThemesEnabled, false)
+		test.AssertType(t, gs.PlaybackPolicy, "queue")
+	})
+}
@@ -3,12 +3,16 @@ package models
 import (
 	"database/sql"
 	"errors"
+	"math/rand"
 	"strings"
 	"time"
 )
 
 const (
 	TIME_LAYOUT = "2006-01-02 15:04:05"
+
+	soundbiteColumns = `id, name, username, user_id, filepath, filehash, created, duration,
+	source_type, source_url, source_title, source_author, start, deleted_at`
 )
 
 // Struct to present a record in the 'soundbites' table
@@ -20,6 +24,70 @@ type Soundbite struct {
 	FilePath string
 	FileHash string
 	Created  time.Time
+	Duration time.Duration
+
+	SourceType   SourceType
+	SourceURL    string        // The video the soundbite was clipped from, empty for uploads
+	SourceTitle  string        // The title of the video or the name of the uploaded file
+	SourceAuthor string        // The channel that uploaded the video
+	Start        time.Duration // Where in the source video the soundbite starts
+	Deleted      time.Time     // When the soundbite was moved to the trash, zero if it is not in the trash
+}
+
+// Where the audio of a soundbite came from
+type SourceType string
+
+const (
+	SourceUnknown SourceType = "" // Soundbites created before sources were recorded
+	SourceYoutube SourceType = "youtube"
+	SourceUpload  SourceType = "upload"
+)
+
+// The orders that soundbites can be listed in
+type SortOrder int
+
+const (
+	SortByName SortOrder = iota
+	SortByNewest
+	SortByPlays
+	SortByCreator
+)
+
+// ORDER BY clauses for each SortOrder, ties are broken by name
+var sortClauses = map[SortOrder]string{
+	SortByName:    `name COLLATE NOCASE`,
+	SortByNewest:  `created DESC, id DESC`,
+	SortByPlays:   `(SELECT COUNT(*) FROM plays WHERE soundbite_id = soundbites.id) DESC, name COLLATE NOCASE`,
+	SortByCreator: `username COLLATE NOCASE, name COLLATE NOCASE`,
+}
+
+// Struct for the options used to list a page of soundbites
+type ListOptions struct {
+	Tag    string // Only list soundbites with this tag, empty lists every soundbite
+	Sort   SortOrder
+	Offset int
+	Limit  int
+}
+
+// How play counts affect the chance of a soundbite being picked at random
+type Weighting int
+
+const (
+	WeightEqual       Weighting = iota // Every soundbite is equally likely
+	WeightMostPlayed                   // Soundbites are as likely as their plays plus one
+	WeightLeastPlayed                  // Soundbites are as likely as the inverse of their plays plus one
+)
+
+// Struct for the options used to pick a random soundbite
+type RandomOptions struct {
+	Tag    string // Only pick soundbites with this tag, empty picks from every soundbite
+	UserID string // Only pick soundbites this user created, empty picks from every user
+	Weight Weighting
+}
+
+// Interface satisfied by both *sql.Row and *sql.Rows
+type scanner interface {
+	Scan(dest ...interface{}) error
 }
 
 // Struct that holds the database connectivity
@@ -44,15 +112,67 @@ func (m *SoundbiteModel) Initialize() error {
 		return err
 	}
 
+	// duration of the soundbite in milliseconds
+	if err := addColumn(m.DB, "soundbites", "duration", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+
+	for _, column := range []string{"source_type", "source_url", "source_title", "source_author"} {
+		if err := addColumn(m.DB, "soundbites", column, "TEXT NOT NULL DEFAULT ''"); err != nil {
+			return err
+		}
+	}
+
+	// start of the soundbite in its source video in milliseconds
+	if err := addColumn(m.DB, "soundbites", "start", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+
+	// when the soundbite was moved to the trash, empty if it is not in the trash
+	if err := addColumn(m.DB, "soundbites", "deleted_at", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+
 	return nil
 }
 
-// Insert Soundbites metadata into the 'soundbites' table
-func (m *SoundbiteModel) Insert(name, username, uid, filepath, filehash string) (int, error) {
-	stmt := `INSERT INTO soundbites (name, username, user_id, filepath, filehash, created)  
-	VALUES(?, ?, ?, ?, ?, datetime('now'));`
+// Scans a row from the 'soundbites' table into a Soundbite
+func scanSoundbite(row scanner) (*Soundbite, error) {
+	var date, deleted string
+	var ms, start int64
+	s := &Soundbite{}
 
-	res, err := m.DB.Exec(stmt, name, username, uid, filepath, filehash)
+	err := row.Scan(&s.ID, &s.Name, &s.Username, &s.UserID, &s.FilePath, &s.FileHash, &date, &ms,
+		&s.SourceType, &s.SourceURL, &s.SourceTitle, &s.SourceAuthor, &start, &deleted)
+	if err != nil {
+		return nil, err
+	}
+
+	if deleted != "" {
+		if s.Deleted, err = time.Parse(TIME_LAYOUT, deleted); err != nil {
+			return nil, err
+		}
+	}
+
+	t, err := time.Parse(TIME_LAYOUT, date)
+	if err != nil {
+		return nil, err
+	}
+
+	s.Created = t
+	s.Duration = time.Duration(ms) * time.Millisecond
+	s.Start = time.Duration(start) * time.Millisecond
+	return s, nil
+}
+
+// Insert Soundbites metadata into the 'soundbites' table, the ID and Created fields are ignored
+func (m *SoundbiteModel) Insert(s *Soundbite) (int, error) {
+	stmt := `INSERT INTO soundbites (name, username, user_id, filepath, filehash, created, duration,
+	source_type, source_url, source_title, source_author, start)
+	VALUES(?, ?, ?, ?, ?, datetime('now'), ?, ?, ?, ?, ?, ?);`
+
+	res, err := m.DB.Exec(stmt, s.Name, s.Username, s.UserID, s.FilePath, s.FileHash, s.Duration.Milliseconds(),
+		s.SourceType, s.SourceURL, s.SourceTitle, s.SourceAuthor, s.Start.Milliseconds())
 	if err != nil {
 		if strings.Contains(err.Error(), "UNIQUE constraint") {
 			return 0, ErrUniqueConstraint
@@ -69,14 +189,11 @@ func (m *SoundbiteModel) Insert(name, username, uid, filepath, filehash string)
 	return int(id), nil
 }
 
-// Gets a Soundbite based on the name command
+// Gets a Soundbite based on the name command, soundbites in the trash are not found
 func (m *SoundbiteModel) Get(name string) (*Soundbite, error) {
-	stmt := `SELECT * FROM soundbites WHERE name = ?;`
+	stmt := `SELECT ` + soundbiteColumns + ` FROM soundbites WHERE name = ? AND deleted_at = '';`
 
-	var date string
-	s := &Soundbite{}
-
-	err := m.DB.QueryRow(stmt, name).Scan(&s.ID, &s.Name, &s.Username, &s.UserID, &s.FilePath, &s.FileHash, &date)
+	s, err := scanSoundbite(m.DB.QueryRow(stmt, name))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrDoesNotExist
@@ -85,18 +202,12 @@ func (m *SoundbiteModel) Get(name string) (*Soundbite, error) {
 		return nil, err
 	}
 
-	t, err := time.Parse(TIME_LAYOUT, date)
-	if err != nil {
-		return nil, err
-	}
-
-	s.Created = t
 	return s, nil
 }
 
-// Get all the soundbites in the 'soundbites' table
+// Get all the soundbites in the 'soundbites' table, including the ones in the trash
 func (m *SoundbiteModel) GetAll() ([]*Soundbite, error) {
-	stmt := `SELECT * FROM soundbites;`
+	stmt := `SELECT ` + soundbiteColumns + ` FROM soundbites;`
 
 	rows, err := m.DB.Query(stmt)
 	if err != nil {
@@ -107,20 +218,11 @@ func (m *SoundbiteModel) GetAll() ([]*Soundbite, error) {
 	soundbites := []*Soundbite{}
 
 	for rows.Next() {
-		var date string
-		s := &Soundbite{}
-
-		err = rows.Scan(&s.ID, &s.Name, &s.Username, &s.UserID, &s.FilePath, &s.FileHash, &date)
+		s, err := scanSoundbite(rows)
 		if err != nil {
 			return nil, err
 		}
 
-		t, err := time.Parse(TIME_LAYOUT, date)
-		if err != nil {
-			return nil, err
-		}
-
-		s.Created = t
 		soundbites = append(soundbites, s)
 	}
 
@@ -135,29 +237,315 @@ func (m *SoundbiteModel) GetAll() ([]*Soundbite, error) {
 	return soundbites, nil
 }
 
-// Check whether a soundbite exists based on the name of the command and it's filehash
+// Gets the names of all the soundbites that are not in the trash
+func (m *SoundbiteModel) Names() ([]string, error) {
+	stmt := `SELECT name FROM soundbites WHERE deleted_at = '' ORDER BY name;`
+
+	rows, err := m.DB.Query(stmt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	names := []string{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+
+		names = append(names, name)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return names, nil
+}
+
+// Gets a page of soundbites and the total number of soundbites matching the options.
+// The 'tags' and 'plays' tables must already exist.
+func (m *SoundbiteModel) List(opts ListOptions) ([]*Soundbite, int, error) {
+	order, ok := sortClauses[opts.Sort]
+	if !ok {
+		order = sortClauses[SortByName]
+	}
+
+	where := `WHERE deleted_at = '' AND (? = '' OR id IN (SELECT soundbite_id FROM tags WHERE tag = ?))`
+
+	var total int
+	stmt := `SELECT COUNT(*) FROM soundbites ` + where + `;`
+	if err := m.DB.QueryRow(stmt, opts.Tag, opts.Tag).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	stmt = `SELECT ` + soundbiteColumns + ` FROM soundbites ` + where + ` ORDER BY ` + order + ` LIMIT ? OFFSET ?;`
+
+	rows, err := m.DB.Query(stmt, opts.Tag, opts.Tag, opts.Limit, opts.Offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	soundbites := []*Soundbite{}
+	for rows.Next() {
+		s, err := scanSoundbite(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		soundbites = append(soundbites, s)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return soundbites, total, nil
+}
+
+// Picks the name of a random soundbite matching the options using r, or the default
+// source when r is nil. The 'tags' and 'plays' tables must already exist.
+func (m *SoundbiteModel) Random(opts RandomOptions, r *rand.Rand) (string, error) {
+	stmt := `SELECT name, (SELECT COUNT(*) FROM plays WHERE soundbite_id = soundbites.id) FROM soundbites
+	WHERE deleted_at = '' AND (?1 = '' OR id IN (SELECT soundbite_id FROM tags WHERE tag = ?1))
+	AND (?2 = '' OR user_id = ?2) ORDER BY id;`
+
+	rows, err := m.DB.Query(stmt, opts.Tag, opts.UserID)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	names := []string{}
+	weights := []float64{}
+
+	for rows.Next() {
+		var name string
+		var plays int
+		if err := rows.Scan(&name, &plays); err != nil {
+			return "", err
+		}
+
+		names = append(names, name)
+		weights = append(weights, playWeight(plays, opts.Weight))
+	}
+
+	if err = rows.Err(); err != nil {
+		return "", err
+	}
+
+	if len(names) == 0 {
+		return "", ErrNoRecords
+	}
+
+	float := rand.Float64
+	if r != nil {
+		float = r.Float64
+	}
+
+	return names[pickWeighted(weights, float())], nil
+}
+
+// Gets the weight of a soundbite with the given number of plays
+func playWeight(plays int, w Weighting) float64 {
+	switch w {
+	case WeightMostPlayed:
+		return float64(plays + 1)
+	case WeightLeastPlayed:
+		return 1 / float64(plays+1)
+	}
+
+	return 1
+}
+
+// Picks the index of a weight where f in [0, 1) selects a point along the total weight
+func pickWeighted(weights []float64, f float64) int {
+	total := 0.0
+	for _, w := range weights {
+		total += w
+	}
+
+	point := f * total
+	for i, w := range weights {
+		if point < w {
+			return i
+		}
+
+		point -= w
+	}
+
+	// rounding can leave the point just past the last weight
+	return len(weights) - 1
+}
+
+// Check whether a soundbite exists based on the name of the command and it's filehash,
+// soundbites in the trash do not count
 func (m *SoundbiteModel) Exists(name, hash string) (bool, error) {
 	var exists bool
 
-	stmt := `SELECT EXISTS(SELECT 1 FROM soundbites WHERE name = ? OR filehash = ?);`
+	stmt := `SELECT EXISTS(SELECT 1 FROM soundbites WHERE deleted_at = '' AND (name = ? OR filehash = ?));`
 	err := m.DB.QueryRow(stmt, name, hash).Scan(&exists)
 
 	return exists, err
 }
 
-// Deletes the soundbite if the user_id and name belong to the same record
-func (m *SoundbiteModel) Delete(name, uid string) error {
-	if exists, _ := m.Exists(name, ""); !exists {
+// Deletes the soundbite regardless of which user created it or whether it is in the trash
+func (m *SoundbiteModel) ForceDelete(name string) error {
+	stmt := `DELETE FROM soundbites WHERE name = ?;`
+
+	res, err := m.DB.Exec(stmt, name)
+	if err != nil {
+		return err
+	}
+
+	c, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if int(c) == 0 {
+		return ErrDoesNotExist
+	}
+
+	return nil
+}
+
+// Moves a soundbite to the trash, it keeps its name, file, tags and plays until it is restored or purged.
+// Its name stays taken by the trashed soundbite so new soundbites, renames and aliases can't use it
+// and restoring it never conflicts.
+func (m *SoundbiteModel) Trash(name string) error {
+	stmt := `UPDATE soundbites SET deleted_at = datetime('now') WHERE name = ? AND deleted_at = '';`
+
+	return m.updateTrashed(stmt, name)
+}
+
+// Takes a soundbite out of the trash
+func (m *SoundbiteModel) Restore(name string) error {
+	stmt := `UPDATE soundbites SET deleted_at = '' WHERE name = ? AND deleted_at != '';`
+
+	return m.updateTrashed(stmt, name)
+}
+
+// Runs an update that moves a soundbite in or out of the trash
+func (m *SoundbiteModel) updateTrashed(stmt, name string) error {
+	res, err := m.DB.Exec(stmt, name)
+	if err != nil {
+		return err
+	}
+
+	c, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if int(c) == 0 {
+		return ErrDoesNotExist
+	}
+
+	return nil
+}
+
+// Gets a soundbite in the trash by name
+func (m *SoundbiteModel) GetTrashed(name string) (*Soundbite, error) {
+	stmt := `SELECT ` + soundbiteColumns + ` FROM soundbites WHERE name = ? AND deleted_at != '';`
+
+	s, err := scanSoundbite(m.DB.QueryRow(stmt, name))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrDoesNotExist
+		}
+
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Gets the soundbites in the trash that were deleted before a time, most recently deleted
+// first. A zero time gets every soundbite in the trash.
+func (m *SoundbiteModel) Trashed(before time.Time) ([]*Soundbite, error) {
+	cutoff := ""
+	if !before.IsZero() {
+		cutoff = before.UTC().Format(TIME_LAYOUT)
+	}
+
+	stmt := `SELECT ` + soundbiteColumns + ` FROM soundbites
+	WHERE deleted_at != '' AND (?1 = '' OR deleted_at < ?1) ORDER BY deleted_at DESC, name;`
+
+	rows, err := m.DB.Query(stmt, cutoff)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	soundbites := []*Soundbite{}
+	for rows.Next() {
+		s, err := scanSoundbite(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		soundbites = append(soundbites, s)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return soundbites, nil
+}
+
+// Replaces the stored filehash of a soundbite, soundbites in the trash are not changed
+func (m *SoundbiteModel) UpdateHash(name, hash string) error {
+	stmt := `UPDATE soundbites SET filehash = ? WHERE name = ? AND deleted_at = '';`
+
+	res, err := m.DB.Exec(stmt, hash, name)
+	if err != nil {
+		return err
+	}
+
+	c, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if int(c) == 0 {
 		return ErrDoesNotExist
 	}
 
-	if err := m.userCreatedSound(name, uid); err != nil {
+	return nil
+}
+
+// Replaces the stored duration of a soundbite, soundbites in the trash are not changed
+func (m *SoundbiteModel) UpdateDuration(name string, duration time.Duration) error {
+	stmt := `UPDATE soundbites SET duration = ? WHERE name = ? AND deleted_at = '';`
+
+	res, err := m.DB.Exec(stmt, duration.Milliseconds(), name)
+	if err != nil {
 		return err
 	}
 
-	stmt := `DELETE FROM soundbites WHERE name = ? AND user_id = ?;`
+	c, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if int(c) == 0 {
+		return ErrDoesNotExist
+	}
+
+	return nil
+}
+
+// Replaces the audio file of a soundbite and the fields that describe it in one update
+// so the soundbite keeps its ID, tags and plays. Soundbites in the trash are not changed.
+func (m *SoundbiteModel) UpdateAudio(name, filepath, filehash string, duration, start time.Duration) error {
+	stmt := `UPDATE soundbites SET filepath = ?, filehash = ?, duration = ?, start = ?
+	WHERE name = ? AND deleted_at = '';`
 
-	res, err := m.DB.Exec(stmt, name, uid)
+	res, err := m.DB.Exec(stmt, filepath, filehash, duration.Milliseconds(), start.Milliseconds(), name)
 	if err != nil {
 		return err
 	}
@@ -174,6 +562,43 @@ func (m *SoundbiteModel) Delete(name, uid string) error {
 	return nil
 }
 
+// Gets the number of soundbites a user has created and their total duration,
+// soundbites in the trash do not count
+func (m *SoundbiteModel) Usage(uid string) (int, time.Duration, error) {
+	var count int
+	var ms int64
+
+	stmt := `SELECT COUNT(*), COALESCE(SUM(duration), 0) FROM soundbites WHERE user_id = ? AND deleted_at = '';`
+	err := m.DB.QueryRow(stmt, uid).Scan(&count, &ms)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return count, time.Duration(ms) * time.Millisecond, nil
+}
+
+// Transfers ownership of a soundbite to another user, soundbites in the trash are not changed
+func (m *SoundbiteModel) UpdateOwner(name, username, uid string) error {
+	stmt := `UPDATE soundbites SET username = ?, user_id = ? WHERE name = ? AND deleted_at = '';`
+
+	res, err := m.DB.Exec(stmt, username, uid, name)
+	if err != nil {
+		return err
+	}
+
+	c, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if int(c) == 0 {
+		return ErrDoesNotExist
+	}
+
+	return nil
+}
+
+// Renames a soundbite, soundbites in the trash are not renamed
 func (m *SoundbiteModel) UpdateName(oldName, newName string) error {
 	exists, err := m.Exists(newName, "")
 	if err != nil {
@@ -184,28 +609,25 @@ func (m *SoundbiteModel) UpdateName(oldName, newName string) error {
 		return ErrUniqueConstraint
 	}
 
-	stmt := `UPDATE soundbites SET name = ? where name = ?`
+	stmt := `UPDATE soundbites SET name = ? WHERE name = ? AND deleted_at = '';`
 
-	_, err = m.DB.Exec(stmt, newName, oldName)
+	res, err := m.DB.Exec(stmt, newName, oldName)
 	if err != nil {
+		// the name can also be taken by an alias or a soundbite in the trash
+		if strings.Contains(err.Error(), "UNIQUE constraint") {
+			return ErrUniqueConstraint
+		}
+
 		return err
 	}
 
-	return nil
-}
-
-// Checks that the user_id of the soundbite belongs to the user requesting the delete
-func (m *SoundbiteModel) userCreatedSound(name, uid string) error {
-	var exists bool
-	stmt := `SELECT EXISTS(SELECT 1 FROM soundbites where name = ? AND user_id = ?);`
-
-	err := m.DB.QueryRow(stmt, name, uid).Scan(&exists)
+	c, err := res.RowsAffected()
 	if err != nil {
 		return err
 	}
 
-	if !exists {
-		return ErrCommandOwnership
+	if int(c) == 0 {
+		return ErrDoesNotExist
 	}
 
 	return nil
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"math/rand"
 	"testing"
 	"time"
 
@@ -15,6 +16,13 @@ var (
 		UserID:   "111111",
 		FilePath: "/path/to/file/1",
 		FileHash: "sha256:111111",
+		Duration: 5 * time.Second,
+
+		SourceType:   SourceYoutube,
+		SourceURL:    "https://www.youtube.com/watch?v=111111",
+		SourceTitle:  "test video 1",
+		SourceAuthor: "test_channel_1",
+		Start:        6 * time.Second,
 	}
 	s2 = &Soundbite{
 		ID:       2,
@@ -23,6 +31,13 @@ var (
 		UserID:   "222222",
 		FilePath: "/path/to/file/2",
 		FileHash: "sha256:222222",
+		Duration: 10 * time.Second,
+
+		SourceType:   SourceYoutube,
+		SourceURL:    "https://www.youtube.com/watch?v=222222",
+		SourceTitle:  "test video 2",
+		SourceAuthor: "test_channel_2",
+		Start:        90500 * time.Millisecond,
 	}
 	s3 = &Soundbite{
 		ID:       3,
@@ -31,11 +46,15 @@ var (
 		UserID:   "333333",
 		FilePath: "/path/to/file/3",
 		FileHash: "sha256:333333",
+		Duration: 2500 * time.Millisecond,
+
+		SourceType:  SourceUpload,
+		SourceTitle: "test3.mp3",
 	}
 )
 
 func mockInsert(m SoundbiteModel, s *Soundbite) (int, error) {
-	return m.Insert(s.Name, s.Username, s.UserID, s.FilePath, s.FileHash)
+	return m.Insert(s)
 }
 
 func TestInsert(t *testing.T) {
@@ -154,6 +173,224 @@ func TestGetAll(t *testing.T) {
 	})
 }
 
+func TestNames(t *testing.T) {
+	t.Run("names from empty table", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		names, err := m.Names()
+		test.AssertError(t, err, nil)
+		test.AssertType(t, names, []string{})
+	})
+
+	t.Run("names from populated table", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		_, _ = mockInsert(m, s3)
+		_, _ = mockInsert(m, s1)
+		_, _ = mockInsert(m, s2)
+
+		names, err := m.Names()
+		test.AssertError(t, err, nil)
+		test.AssertType(t, names, []string{s1.Name, s2.Name, s3.Name})
+	})
+}
+
+func listNames(sounds []*Soundbite) []string {
+	names := []string{}
+	for _, s := range sounds {
+		names = append(names, s.Name)
+	}
+
+	return names
+}
+
+func TestList(t *testing.T) {
+	tt := []struct {
+		description string
+		opts        ListOptions
+		expected    []string
+		total       int
+	}{
+		{
+			description: "list sorted by name",
+			opts:        ListOptions{Sort: SortByName, Limit: 10},
+			expected:    []string{s1.Name, s2.Name, s3.Name},
+			total:       3,
+		},
+		{
+			description: "list sorted by newest",
+			opts:        ListOptions{Sort: SortByNewest, Limit: 10},
+			expected:    []string{s3.Name, s2.Name, s1.Name},
+			total:       3,
+		},
+		{
+			description: "list sorted by plays",
+			opts:        ListOptions{Sort: SortByPlays, Limit: 10},
+			expected:    []string{s2.Name, s3.Name, s1.Name},
+			total:       3,
+		},
+		{
+			description: "list sorted by creator",
+			opts:        ListOptions{Sort: SortByCreator, Limit: 10},
+			expected:    []string{s3.Name, s1.Name, s2.Name},
+			total:       3,
+		},
+		{
+			description: "list second page",
+			opts:        ListOptions{Sort: SortByName, Offset: 2, Limit: 2},
+			expected:    []string{s3.Name},
+			total:       3,
+		},
+		{
+			description: "list with tag",
+			opts:        ListOptions{Tag: "memes", Sort: SortByName, Limit: 10},
+			expected:    []string{s1.Name, s3.Name},
+			total:       2,
+		},
+		{
+			description: "list with unused tag",
+			opts:        ListOptions{Tag: "anime", Sort: SortByName, Limit: 10},
+			expected:    []string{},
+			total:       0,
+		},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			m, teardown := modelsTestSetup(t)
+			defer teardown()
+
+			tm := TagModel{DB: m.DB}
+			pm := PlayModel{DB: m.DB}
+			initializeTestModels(t, &tm, &pm)
+
+			_, _ = mockInsert(m, s1)
+			_, _ = mockInsert(m, s2)
+			_, _ = m.Insert(&Soundbite{Name: s3.Name, Username: "a_username", UserID: s3.UserID, FilePath: s3.FilePath, FileHash: s3.FileHash, Duration: s3.Duration})
+			_ = tm.Add(s1.Name, "memes")
+			_ = tm.Add(s3.Name, "memes")
+			_ = pm.Record(s2.ID, s1.UserID, "guild")
+			_ = pm.Record(s2.ID, s1.UserID, "guild")
+			_ = pm.Record(s3.ID, s1.UserID, "guild")
+
+			sounds, total, err := m.List(tc.opts)
+			test.AssertError(t, err, nil)
+			test.AssertType(t, listNames(sounds), tc.expected)
+			test.AssertType(t, total, tc.total)
+		})
+	}
+}
+
+func TestRandom(t *testing.T) {
+	tt := []struct {
+		description string
+		opts        RandomOptions
+		expected    []string
+	}{
+		{"random from every soundbite", RandomOptions{}, []string{s1.Name, s2.Name, s3.Name}},
+		{"random with tag", RandomOptions{Tag: "memes"}, []string{s1.Name, s3.Name}},
+		{"random from user", RandomOptions{UserID: s2.UserID}, []string{s2.Name}},
+		{"random with tag from user", RandomOptions{Tag: "memes", UserID: s3.UserID}, []string{s3.Name}},
+		{"random weighted by plays", RandomOptions{Weight: WeightMostPlayed}, []string{s1.Name, s2.Name, s3.Name}},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			m, teardown := modelsTestSetup(t)
+			defer teardown()
+
+			tm := TagModel{DB: m.DB}
+			initializeTestModels(t, &tm, &PlayModel{DB: m.DB})
+
+			_, _ = mockInsert(m, s1)
+			_, _ = mockInsert(m, s2)
+			_, _ = mockInsert(m, s3)
+			_ = tm.Add(s1.Name, "memes")
+			_ = tm.Add(s3.Name, "memes")
+
+			r := rand.New(rand.NewSource(1))
+			picked := make(map[string]bool)
+
+			for i := 0; i < 50; i++ {
+				name, err := m.Random(tc.opts, r)
+				test.AssertError(t, err, nil)
+				picked[name] = true
+			}
+
+			expected := make(map[string]bool)
+			for _, name := range tc.expected {
+				expected[name] = true
+			}
+
+			test.AssertType(t, picked, expected)
+		})
+	}
+
+	t.Run("random with no matches", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		initializeTestModels(t, &TagModel{DB: m.DB}, &PlayModel{DB: m.DB})
+
+		_, _ = mockInsert(m, s1)
+
+		_, err := m.Random(RandomOptions{Tag: "memes"}, nil)
+		test.AssertError(t, err, ErrNoRecords)
+	})
+
+	t.Run("random prefers weighted soundbites", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		pm := PlayModel{DB: m.DB}
+		initializeTestModels(t, &TagModel{DB: m.DB}, &pm)
+
+		_, _ = mockInsert(m, s1)
+		_, _ = mockInsert(m, s2)
+		for i := 0; i < 9; i++ {
+			_ = pm.Record(s1.ID, s1.UserID, "guild")
+		}
+
+		r := rand.New(rand.NewSource(1))
+		counts := make(map[string]int)
+
+		for i := 0; i < 1000; i++ {
+			most, _ := m.Random(RandomOptions{Weight: WeightMostPlayed}, r)
+			least, _ := m.Random(RandomOptions{Weight: WeightLeastPlayed}, r)
+			counts["most:"+most]++
+			counts["least:"+least]++
+		}
+
+		if counts["most:"+s1.Name] < 800 || counts["least:"+s2.Name] < 800 {
+			t.Fatalf("got: %v, expected the weighted soundbites to be picked most", counts)
+		}
+	})
+}
+
+func TestPickWeighted(t *testing.T) {
+	weights := []float64{1, 2, 1}
+
+	tt := []struct {
+		f        float64
+		expected int
+	}{
+		{0, 0},
+		{0.24, 0},
+		{0.25, 1},
+		{0.74, 1},
+		{0.75, 2},
+		{0.9999999999, 2},
+	}
+
+	for _, tc := range tt {
+		test.AssertType(t, pickWeighted(weights, tc.f), tc.expected)
+	}
+}
+
 func TestExists(t *testing.T) {
 	t.Run("exists in an empty table", func(t *testing.T) {
 		m, teardown := modelsTestSetup(t)
@@ -191,44 +428,362 @@ func TestExists(t *testing.T) {
 	})
 }
 
-func TestDelete(t *testing.T) {
-	t.Run("delete from empty table", func(t *testing.T) {
+func TestForceDelete(t *testing.T) {
+	t.Run("force delete from empty table", func(t *testing.T) {
 		m, teardown := modelsTestSetup(t)
 		defer teardown()
 
-		err := m.Delete(s1.Name, s1.UserID)
+		err := m.ForceDelete(s1.Name)
 		test.AssertError(t, err, ErrDoesNotExist)
 	})
 
-	t.Run("delete from table", func(t *testing.T) {
+	t.Run("force delete soundbite created by another user", func(t *testing.T) {
 		m, teardown := modelsTestSetup(t)
 		defer teardown()
 
 		_, _ = mockInsert(m, s1)
+		_, _ = mockInsert(m, s2)
 
-		err := m.Delete(s1.Name, s1.UserID)
+		err := m.ForceDelete(s1.Name)
 		test.AssertError(t, err, nil)
 
-		sounds, err := m.GetAll()
-		test.AssertError(t, err, ErrNoRecords)
-		if len(sounds) != 0 {
-			t.Fatalf("got: %v, expected: %v", len(sounds), 0)
+		b, err := m.Exists(s1.Name, "")
+		test.AssertError(t, err, nil)
+		test.AssertType(t, b, false)
+
+		b, err = m.Exists(s2.Name, "")
+		test.AssertError(t, err, nil)
+		test.AssertType(t, b, true)
+	})
+}
+
+func TestTrash(t *testing.T) {
+	t.Run("trash and restore soundbite", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		_, _ = mockInsert(m, s1)
+		_, _ = mockInsert(m, s2)
+
+		err := m.Trash(s1.Name)
+		test.AssertError(t, err, nil)
+
+		_, err = m.Get(s1.Name)
+		test.AssertError(t, err, ErrDoesNotExist)
+
+		b, _ := m.Exists(s1.Name, "")
+		test.AssertType(t, b, false)
+
+		names, _ := m.Names()
+		test.AssertType(t, names, []string{s2.Name})
+
+		count, _, _ := m.Usage(s1.UserID)
+		test.AssertType(t, count, 0)
+
+		sound, err := m.GetTrashed(s1.Name)
+		test.AssertError(t, err, nil)
+		test.AssertType(t, sound.Deleted.IsZero(), false)
+
+		err = m.Trash(s1.Name)
+		test.AssertError(t, err, ErrDoesNotExist)
+
+		err = m.Restore(s1.Name)
+		test.AssertError(t, err, nil)
+
+		sound, err = m.Get(s1.Name)
+		test.AssertError(t, err, nil)
+		test.AssertType(t, sound.Deleted.IsZero(), true)
+
+		err = m.Restore(s1.Name)
+		test.AssertError(t, err, ErrDoesNotExist)
+
+		_, err = m.GetTrashed(s1.Name)
+		test.AssertError(t, err, ErrDoesNotExist)
+	})
+
+	t.Run("names of trashed soundbites stay taken", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		_, _ = mockInsert(m, s1)
+		_ = m.Trash(s1.Name)
+
+		_, err := mockInsert(m, s1)
+		test.AssertError(t, err, ErrUniqueConstraint)
+
+		_, _ = mockInsert(m, s2)
+		err = m.UpdateName(s2.Name, s1.Name)
+		test.AssertError(t, err, ErrUniqueConstraint)
+
+		am := AliasModel{DB: m.DB}
+		initializeTestModels(t, &am)
+
+		err = am.Insert(s1.Name, s2.Name, s2.Username, s2.UserID)
+		test.AssertError(t, err, ErrUniqueConstraint)
+
+		err = m.Restore(s1.Name)
+		test.AssertError(t, err, nil)
+	})
+
+	t.Run("trashed soundbites deleted before a time", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		_, _ = mockInsert(m, s1)
+		_, _ = mockInsert(m, s2)
+		_ = m.Trash(s1.Name)
+
+		tt := []struct {
+			description string
+			input       time.Time
+			expected    int
+		}{
+			{"every trashed soundbite", time.Time{}, 1},
+			{"deleted before now", time.Now().Add(time.Hour), 1},
+			{"deleted before an hour ago", time.Now().Add(-time.Hour), 0},
+		}
+
+		for _, tc := range tt {
+			tc := tc
+			t.Run(tc.description, func(t *testing.T) {
+				sounds, err := m.Trashed(tc.input)
+				test.AssertError(t, err, nil)
+				test.AssertType(t, len(sounds), tc.expected)
+			})
 		}
 	})
 }
 
-func TestUserCreatedSound(t *testing.T) {
-	t.Run("test user created sound", func(t *testing.T) {
+func TestUpdateHash(t *testing.T) {
+	t.Run("update hash of soundbite", func(t *testing.T) {
 		m, teardown := modelsTestSetup(t)
 		defer teardown()
 
 		_, _ = mockInsert(m, s1)
 
-		err := m.userCreatedSound(s1.Name, s1.UserID)
+		err := m.UpdateHash(s1.Name, "sha256:999999")
+		test.AssertError(t, err, nil)
+
+		s, err := m.Get(s1.Name)
 		test.AssertError(t, err, nil)
+		test.AssertType(t, s.FileHash, "sha256:999999")
+	})
+
+	t.Run("update hash of non-existent soundbite", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
 
-		err = m.userCreatedSound(s1.Name, s2.UserID)
-		test.AssertError(t, err, ErrCommandOwnership)
+		err := m.UpdateHash(s1.Name, "sha256:999999")
+		test.AssertError(t, err, ErrDoesNotExist)
+	})
+
+	t.Run("update hash of trashed soundbite", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		_, _ = mockInsert(m, s1)
+		_ = m.Trash(s1.Name)
+
+		err := m.UpdateHash(s1.Name, "sha256:999999")
+		test.AssertError(t, err, ErrDoesNotExist)
+
+		s, _ := m.GetTrashed(s1.Name)
+		test.AssertType(t, s.FileHash, s1.FileHash)
+	})
+}
+
+func TestUpdateOwner(t *testing.T) {
+	t.Run("transfer soundbite to another user", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		_, _ = mockInsert(m, s1)
+
+		err := m.UpdateOwner(s1.Name, s2.Username, s2.UserID)
+		test.AssertError(t, err, nil)
+
+		s, err := m.Get(s1.Name)
+		test.AssertError(t, err, nil)
+		test.AssertType(t, s.Username, s2.Username)
+		test.AssertType(t, s.UserID, s2.UserID)
+	})
+
+	t.Run("transfer non-existent soundbite", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		err := m.UpdateOwner(s1.Name, s2.Username, s2.UserID)
+		test.AssertError(t, err, ErrDoesNotExist)
+	})
+
+	t.Run("transfer trashed soundbite", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		_, _ = mockInsert(m, s1)
+		_ = m.Trash(s1.Name)
+
+		err := m.UpdateOwner(s1.Name, s2.Username, s2.UserID)
+		test.AssertError(t, err, ErrDoesNotExist)
+
+		s, _ := m.GetTrashed(s1.Name)
+		test.AssertType(t, s.UserID, s1.UserID)
+	})
+}
+
+func TestUpdateDuration(t *testing.T) {
+	t.Run("update duration of soundbite", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		_, _ = mockInsert(m, s1)
+
+		err := m.UpdateDuration(s1.Name, 7*time.Second)
+		test.AssertError(t, err, nil)
+
+		s, err := m.Get(s1.Name)
+		test.AssertError(t, err, nil)
+		test.AssertType(t, s.Duration, 7*time.Second)
+	})
+
+	t.Run("update duration of non-existent soundbite", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		err := m.UpdateDuration(s1.Name, 7*time.Second)
+		test.AssertError(t, err, ErrDoesNotExist)
+	})
+
+	t.Run("update duration of trashed soundbite", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		_, _ = mockInsert(m, s1)
+		_ = m.Trash(s1.Name)
+
+		err := m.UpdateDuration(s1.Name, 7*time.Second)
+		test.AssertError(t, err, ErrDoesNotExist)
+
+		s, _ := m.GetTrashed(s1.Name)
+		test.AssertType(t, s.Duration, s1.Duration)
+	})
+}
+
+func TestUpdateAudio(t *testing.T) {
+	t.Run("update audio of soundbite", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		tm := TagModel{DB: m.DB}
+		pm := PlayModel{DB: m.DB}
+		initializeTestModels(t, &tm, &pm)
+
+		_, _ = mockInsert(m, s1)
+		_ = tm.Add(s1.Name, "memes")
+		_ = pm.Record(s1.ID, s2.UserID, "guild")
+
+		err := m.UpdateAudio(s1.Name, "/path/to/file/new", "sha256:999999", 3*time.Second, 12*time.Second)
+		test.AssertError(t, err, nil)
+
+		s, err := m.Get(s1.Name)
+		test.AssertError(t, err, nil)
+		test.AssertType(t, s.ID, s1.ID)
+		test.AssertType(t, s.FilePath, "/path/to/file/new")
+		test.AssertType(t, s.FileHash, "sha256:999999")
+		test.AssertType(t, s.Duration, 3*time.Second)
+		test.AssertType(t, s.Start, 12*time.Second)
+		test.AssertType(t, s.SourceURL, s1.SourceURL)
+
+		tags, _ := tm.Get(s1.Name)
+		test.AssertType(t, tags, []string{"memes"})
+
+		count, _ := pm.Count(s1.ID)
+		test.AssertType(t, count, 1)
+	})
+
+	t.Run("update audio of non-existent soundbite", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		err := m.UpdateAudio(s1.Name, "/path/to/file/new", "sha256:999999", 3*time.Second, 0)
+		test.AssertError(t, err, ErrDoesNotExist)
+	})
+
+	t.Run("update audio of trashed soundbite", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		_, _ = mockInsert(m, s1)
+		_ = m.Trash(s1.Name)
+
+		err := m.UpdateAudio(s1.Name, "/path/to/file/new", "sha256:999999", 3*time.Second, 0)
+		test.AssertError(t, err, ErrDoesNotExist)
+
+		s, _ := m.GetTrashed(s1.Name)
+		test.AssertType(t, s.FilePath, s1.FilePath)
+	})
+}
+
+func TestUsage(t *testing.T) {
+	t.Run("usage of user without soundbites", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		_, _ = mockInsert(m, s1)
+
+		count, total, err := m.Usage(s2.UserID)
+		test.AssertError(t, err, nil)
+		test.AssertType(t, count, 0)
+		test.AssertType(t, total, time.Duration(0))
+	})
+
+	t.Run("usage of user with soundbites", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		_, _ = mockInsert(m, s1)
+		_, _ = mockInsert(m, s2)
+		_, _ = m.Insert(&Soundbite{Name: s3.Name, Username: s1.Username, UserID: s1.UserID, FilePath: s3.FilePath, FileHash: s3.FileHash, Duration: s3.Duration})
+
+		count, total, err := m.Usage(s1.UserID)
+		test.AssertError(t, err, nil)
+		test.AssertType(t, count, 2)
+		test.AssertType(t, total, 7500*time.Millisecond)
+	})
+}
+
+func TestInitializeMigration(t *testing.T) {
+	t.Run("initialize table created without duration or source", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		_, err := m.DB.Exec(`DROP TABLE soundbites;
+		CREATE TABLE soundbites (
+			id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			username TEXT NOT NULL,
+			user_id	TEXT NOT NULL,
+			filepath TEXT NOT NULL,
+			filehash TEXT NOT NULL,
+			created TEXT NOT NULL,
+			UNIQUE(name)
+		);
+		INSERT INTO soundbites (name, username, user_id, filepath, filehash, created)
+		VALUES('test1', 'test_username_1', '111111', '/path/to/file/1', 'sha256:111111', datetime('now'));`)
+		test.AssertError(t, err, nil)
+
+		err = m.Initialize()
+		test.AssertError(t, err, nil)
+
+		s, err := m.Get(s1.Name)
+		test.AssertError(t, err, nil)
+		test.AssertType(t, s.Duration, time.Duration(0))
+		test.AssertType(t, s.SourceType, SourceUnknown)
+		test.AssertType(t, s.SourceURL, "")
+		test.AssertType(t, s.Start, time.Duration(0))
+
+		err = m.Initialize()
+		test.AssertError(t, err, nil)
 	})
 }
 
@@ -244,6 +799,20 @@ func updateNameTestFunc(t *testing.T, oldName, newName string, expectedErr error
 	test.AssertError(t, err, expectedErr)
 }
 
+func updateTrashedNameTestFunc(t *testing.T, oldName, newName string, expectedErr error) {
+	m, teardown := modelsTestSetup(t)
+	defer teardown()
+
+	_, _ = mockInsert(m, s1)
+	_ = m.Trash(s1.Name)
+
+	err := m.UpdateName(oldName, newName)
+	test.AssertError(t, err, expectedErr)
+
+	_, err = m.GetTrashed(oldName)
+	test.AssertError(t, err, nil)
+}
+
 func TestUpdateName(t *testing.T) {
 	tt := []struct {
 		description string
@@ -273,6 +842,13 @@ func TestUpdateName(t *testing.T) {
 			expectedErr: ErrUniqueConstraint,
 			testFunc:    updateNameTestFunc,
 		},
+		{
+			description: "update trashed soundbite",
+			oldName:     s1.Name,
+			newName:     s3.Name,
+			expectedErr: ErrDoesNotExist,
+			testFunc:    updateTrashedNameTestFunc,
+		},
 	}
 
 	for _, tc := range tt {
@@ -0,0 +1,185 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+)
+
+// Struct for the number of soundbites that have a tag
+type TagCount struct {
+	Tag   string
+	Count int
+}
+
+// Struct that holds the database connectivity for the 'tags' table
+type TagModel struct {
+	DB *sql.DB
+}
+
+// Initialize the 'tags' table in the sqlite db, the 'soundbites' table must already exist.
+// Tags are removed along with their soundbite and follow it when it is renamed.
+func (m *TagModel) Initialize() error {
+	stmt := `CREATE TABLE IF NOT EXISTS tags (
+		soundbite_id INTEGER NOT NULL REFERENCES soundbites(id),
+		tag TEXT NOT NULL,
+		UNIQUE(soundbite_id, tag)
+	);
+	CREATE INDEX IF NOT EXISTS tags_tag ON tags(tag);
+	CREATE TRIGGER IF NOT EXISTS tags_soundbite_delete AFTER DELETE ON soundbites
+	BEGIN
+		DELETE FROM tags WHERE soundbite_id = OLD.id;
+	END;`
+
+	if _, err := m.DB.Exec(stmt); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Gets the id of a soundbite that is not in the trash by its name
+func (m *TagModel) soundbiteID(name string) (int, error) {
+	var id int
+
+	stmt := `SELECT id FROM soundbites WHERE name = ? AND deleted_at = '';`
+	err := m.DB.QueryRow(stmt, name).Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, ErrDoesNotExist
+		}
+
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// Adds tags to a soundbite, tags the soundbite already has are ignored
+func (m *TagModel) Add(name string, tags ...string) error {
+	id, err := m.soundbiteID(name)
+	if err != nil {
+		return err
+	}
+
+	stmt := `INSERT OR IGNORE INTO tags (soundbite_id, tag) VALUES(?, ?);`
+	for _, tag := range tags {
+		if _, err := m.DB.Exec(stmt, id, tag); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Removes tags from a soundbite, tags the soundbite does not have are ignored
+func (m *TagModel) Remove(name string, tags ...string) error {
+	id, err := m.soundbiteID(name)
+	if err != nil {
+		return err
+	}
+
+	stmt := `DELETE FROM tags WHERE soundbite_id = ? AND tag = ?;`
+	for _, tag := range tags {
+		if _, err := m.DB.Exec(stmt, id, tag); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Gets the tags of a soundbite in alphabetical order
+func (m *TagModel) Get(name string) ([]string, error) {
+	id, err := m.soundbiteID(name)
+	if err != nil {
+		return nil, err
+	}
+
+	stmt := `SELECT tag FROM tags WHERE soundbite_id = ? ORDER BY tag;`
+
+	rows, err := m.DB.Query(stmt, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := []string{}
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			return nil, err
+		}
+
+		tags = append(tags, tag)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+// Gets all the soundbites that have a tag
+func (m *TagModel) Soundbites(tag string) ([]*Soundbite, error) {
+	stmt := `SELECT ` + soundbiteColumns + ` FROM soundbites
+	WHERE deleted_at = '' AND id IN (SELECT soundbite_id FROM tags WHERE tag = ?);`
+
+	rows, err := m.DB.Query(stmt, tag)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	soundbites := []*Soundbite{}
+	for rows.Next() {
+		s, err := scanSoundbite(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		soundbites = append(soundbites, s)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(soundbites) == 0 {
+		return soundbites, ErrNoRecords
+	}
+
+	return soundbites, nil
+}
+
+// Gets every tag and the number of soundbites that have it, most used tags first
+func (m *TagModel) Counts() ([]*TagCount, error) {
+	stmt := `SELECT tag, COUNT(*) AS c FROM tags WHERE soundbite_id IN (SELECT id FROM soundbites WHERE deleted_at = '')
+	GROUP BY tag ORDER BY c DESC, tag;`
+
+	rows, err := m.DB.Query(stmt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := []*TagCount{}
+	for rows.Next() {
+		c := &TagCount{}
+		if err := rows.Scan(&c.Tag, &c.Count); err != nil {
+			return nil, err
+		}
+
+		counts = append(counts, c)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(counts) == 0 {
+		return counts, ErrNoRecords
+	}
+
+	return counts, nil
+}
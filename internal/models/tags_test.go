@@ -0,0 +1,212 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	test "github.com/tweekes0/pal-bot/internal/testing"
+)
+
+func TestTagAdd(t *testing.T) {
+	t.Run("add tags to soundbite", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		tm := TagModel{DB: m.DB}
+		initializeTestModels(t, &tm)
+
+		_, _ = mockInsert(m, s1)
+
+		err := tm.Add(s1.Name, "memes", "anime")
+		test.AssertError(t, err, nil)
+
+		tags, err := tm.Get(s1.Name)
+		test.AssertError(t, err, nil)
+		test.AssertType(t, tags, []string{"anime", "memes"})
+	})
+
+	t.Run("add duplicate tags to soundbite", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		tm := TagModel{DB: m.DB}
+		initializeTestModels(t, &tm)
+
+		_, _ = mockInsert(m, s1)
+
+		err := tm.Add(s1.Name, "memes", "memes")
+		test.AssertError(t, err, nil)
+
+		err = tm.Add(s1.Name, "memes")
+		test.AssertError(t, err, nil)
+
+		tags, err := tm.Get(s1.Name)
+		test.AssertError(t, err, nil)
+		test.AssertType(t, tags, []string{"memes"})
+	})
+
+	t.Run("add tags to non-existent soundbite", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		tm := TagModel{DB: m.DB}
+		initializeTestModels(t, &tm)
+
+		err := tm.Add(s1.Name, "memes")
+		test.AssertError(t, err, ErrDoesNotExist)
+	})
+}
+
+func TestTagRemove(t *testing.T) {
+	t.Run("remove tags from soundbite", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		tm := TagModel{DB: m.DB}
+		initializeTestModels(t, &tm)
+
+		_, _ = mockInsert(m, s1)
+		_ = tm.Add(s1.Name, "memes", "anime", "games")
+
+		err := tm.Remove(s1.Name, "memes", "games", "missing")
+		test.AssertError(t, err, nil)
+
+		tags, err := tm.Get(s1.Name)
+		test.AssertError(t, err, nil)
+		test.AssertType(t, tags, []string{"anime"})
+	})
+
+	t.Run("remove tags from non-existent soundbite", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		tm := TagModel{DB: m.DB}
+		initializeTestModels(t, &tm)
+
+		err := tm.Remove(s1.Name, "memes")
+		test.AssertError(t, err, ErrDoesNotExist)
+	})
+}
+
+func TestTagGet(t *testing.T) {
+	t.Run("get tags of untagged soundbite", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		tm := TagModel{DB: m.DB}
+		initializeTestModels(t, &tm)
+
+		_, _ = mockInsert(m, s1)
+
+		tags, err := tm.Get(s1.Name)
+		test.AssertError(t, err, nil)
+		test.AssertType(t, tags, []string{})
+	})
+
+	t.Run("get tags after soundbite is renamed", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		tm := TagModel{DB: m.DB}
+		initializeTestModels(t, &tm)
+
+		_, _ = mockInsert(m, s1)
+		_ = tm.Add(s1.Name, "memes")
+		_ = m.UpdateName(s1.Name, s3.Name)
+
+		tags, err := tm.Get(s3.Name)
+		test.AssertError(t, err, nil)
+		test.AssertType(t, tags, []string{"memes"})
+	})
+
+	t.Run("get tags after soundbite is deleted", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		tm := TagModel{DB: m.DB}
+		initializeTestModels(t, &tm)
+
+		_, _ = mockInsert(m, s1)
+		_ = tm.Add(s1.Name, "memes")
+		_ = m.ForceDelete(s1.Name)
+
+		counts, err := tm.Counts()
+		test.AssertError(t, err, ErrNoRecords)
+		test.AssertType(t, len(counts), 0)
+	})
+}
+
+func TestTagSoundbites(t *testing.T) {
+	t.Run("get soundbites with tag", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		tm := TagModel{DB: m.DB}
+		initializeTestModels(t, &tm)
+
+		_, _ = mockInsert(m, s1)
+		_, _ = mockInsert(m, s2)
+		_, _ = mockInsert(m, s3)
+		_ = tm.Add(s1.Name, "memes")
+		_ = tm.Add(s3.Name, "memes", "anime")
+
+		sounds, err := tm.Soundbites("memes")
+		for _, s := range sounds {
+			s.Created = time.Time{}
+		}
+
+		test.AssertError(t, err, nil)
+		test.AssertType(t, sounds, []*Soundbite{s1, s3})
+	})
+
+	t.Run("get soundbites with unused tag", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		tm := TagModel{DB: m.DB}
+		initializeTestModels(t, &tm)
+
+		_, _ = mockInsert(m, s1)
+
+		sounds, err := tm.Soundbites("memes")
+		test.AssertError(t, err, ErrNoRecords)
+		test.AssertType(t, len(sounds), 0)
+	})
+}
+
+func TestTagCounts(t *testing.T) {
+	t.Run("count tags", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		tm := TagModel{DB: m.DB}
+		initializeTestModels(t, &tm)
+
+		_, _ = mockInsert(m, s1)
+		_, _ = mockInsert(m, s2)
+		_, _ = mockInsert(m, s3)
+		_ = tm.Add(s1.Name, "memes", "games")
+		_ = tm.Add(s2.Name, "anime")
+		_ = tm.Add(s3.Name, "memes", "anime")
+
+		counts, err := tm.Counts()
+		test.AssertError(t, err, nil)
+		test.AssertType(t, counts, []*TagCount{
+			{Tag: "anime", Count: 2},
+			{Tag: "memes", Count: 2},
+			{Tag: "games", Count: 1},
+		})
+	})
+
+	t.Run("count tags in empty table", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		tm := TagModel{DB: m.DB}
+		initializeTestModels(t, &tm)
+
+		counts, err := tm.Counts()
+		test.AssertError(t, err, ErrNoRecords)
+		test.AssertType(t, len(counts), 0)
+	})
+}
@@ -0,0 +1,102 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+)
+
+// When a member's theme sound is played
+type ThemeKind string
+
+const (
+	ThemeIntro ThemeKind = "intro" // Played when the member joins a voice channel
+	ThemeOutro ThemeKind = "outro" // Played when the member leaves a voice channel
+)
+
+// Struct that holds the database connectivity for the 'themes' table
+type ThemeModel struct {
+	DB *sql.DB
+}
+
+// Initialize the 'themes' table in the sqlite db, the 'soundbites' table must already exist.
+// Themes are removed along with their soundbite.
+func (m *ThemeModel) Initialize() error {
+	stmt := `CREATE TABLE IF NOT EXISTS themes (
+		user_id TEXT NOT NULL,
+		guild_id TEXT NOT NULL,
+		kind TEXT NOT NULL,
+		soundbite_id INTEGER NOT NULL REFERENCES soundbites(id),
+		UNIQUE(user_id, guild_id, kind)
+	);
+	CREATE TRIGGER IF NOT EXISTS themes_soundbite_delete AFTER DELETE ON soundbites
+	BEGIN
+		DELETE FROM themes WHERE soundbite_id = OLD.id;
+	END;`
+
+	if _, err := m.DB.Exec(stmt); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Sets a member's theme sound in a guild, replacing the one they had
+func (m *ThemeModel) Set(uid, guildID string, kind ThemeKind, name string) error {
+	stmt := `INSERT INTO themes (user_id, guild_id, kind, soundbite_id)
+	SELECT ?, ?, ?, id FROM soundbites WHERE name = ? AND deleted_at = ''
+	ON CONFLICT(user_id, guild_id, kind) DO UPDATE SET soundbite_id = excluded.soundbite_id;`
+
+	res, err := m.DB.Exec(stmt, uid, guildID, kind, name)
+	if err != nil {
+		return err
+	}
+
+	c, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if int(c) == 0 {
+		return ErrDoesNotExist
+	}
+
+	return nil
+}
+
+// Removes a member's theme sound in a guild
+func (m *ThemeModel) Clear(uid, guildID string, kind ThemeKind) error {
+	stmt := `DELETE FROM themes WHERE user_id = ? AND guild_id = ? AND kind = ?;`
+
+	res, err := m.DB.Exec(stmt, uid, guildID, kind)
+	if err != nil {
+		return err
+	}
+
+	c, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if int(c) == 0 {
+		return ErrDoesNotExist
+	}
+
+	return nil
+}
+
+// Gets a member's theme sound in a guild, a theme whose soundbite is in the trash is not found
+func (m *ThemeModel) Get(uid, guildID string, kind ThemeKind) (*Soundbite, error) {
+	stmt := `SELECT ` + soundbiteColumns + ` FROM soundbites
+	WHERE deleted_at = '' AND id = (SELECT soundbite_id FROM themes WHERE user_id = ? AND guild_id = ? AND kind = ?);`
+
+	s, err := scanSoundbite(m.DB.QueryRow(stmt, uid, guildID, kind))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrDoesNotExist
+		}
+
+		return nil, err
+	}
+
+	return s, nil
+}
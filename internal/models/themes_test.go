@@ -0,0 +1,109 @@
+package models
+
+import (
+	"testing"
+
+	test "github.com/tweekes0/pal-bot/internal/testing"
+)
+
+func TestThemes(t *testing.T) {
+	t.Run("set and replace themes", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		thm := ThemeModel{DB: m.DB}
+		initializeTestModels(t, &thm)
+
+		_, _ = mockInsert(m, s1)
+		_, _ = mockInsert(m, s2)
+
+		err := thm.Set(s1.UserID, "guild", ThemeIntro, s1.Name)
+		test.AssertError(t, err, nil)
+
+		err = thm.Set(s1.UserID, "guild", ThemeOutro, s2.Name)
+		test.AssertError(t, err, nil)
+
+		intro, err := thm.Get(s1.UserID, "guild", ThemeIntro)
+		test.AssertError(t, err, nil)
+		test.AssertType(t, intro.Name, s1.Name)
+
+		err = thm.Set(s1.UserID, "guild", ThemeIntro, s2.Name)
+		test.AssertError(t, err, nil)
+
+		intro, err = thm.Get(s1.UserID, "guild", ThemeIntro)
+		test.AssertError(t, err, nil)
+		test.AssertType(t, intro.Name, s2.Name)
+
+		_, err = thm.Get(s1.UserID, "other_guild", ThemeIntro)
+		test.AssertError(t, err, ErrDoesNotExist)
+	})
+
+	t.Run("set theme to non-existent soundbite", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		thm := ThemeModel{DB: m.DB}
+		initializeTestModels(t, &thm)
+
+		err := thm.Set(s1.UserID, "guild", ThemeIntro, s1.Name)
+		test.AssertError(t, err, ErrDoesNotExist)
+	})
+
+	t.Run("clear themes", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		thm := ThemeModel{DB: m.DB}
+		initializeTestModels(t, &thm)
+
+		_, _ = mockInsert(m, s1)
+		_ = thm.Set(s1.UserID, "guild", ThemeIntro, s1.Name)
+
+		err := thm.Clear(s1.UserID, "guild", ThemeIntro)
+		test.AssertError(t, err, nil)
+
+		_, err = thm.Get(s1.UserID, "guild", ThemeIntro)
+		test.AssertError(t, err, ErrDoesNotExist)
+
+		err = thm.Clear(s1.UserID, "guild", ThemeIntro)
+		test.AssertError(t, err, ErrDoesNotExist)
+	})
+
+	t.Run("themes are removed with their soundbite", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		thm := ThemeModel{DB: m.DB}
+		initializeTestModels(t, &thm)
+
+		_, _ = mockInsert(m, s1)
+		_ = thm.Set(s1.UserID, "guild", ThemeIntro, s1.Name)
+
+		_ = m.ForceDelete(s1.Name)
+
+		_, err := thm.Get(s1.UserID, "guild", ThemeIntro)
+		test.AssertError(t, err, ErrDoesNotExist)
+	})
+
+	t.Run("themes wait while their soundbite is in the trash", func(t *testing.T) {
+		m, teardown := modelsTestSetup(t)
+		defer teardown()
+
+		thm := ThemeModel{DB: m.DB}
+		initializeTestModels(t, &thm)
+
+		_, _ = mockInsert(m, s1)
+		_ = thm.Set(s1.UserID, "guild", ThemeIntro, s1.Name)
+
+		_ = m.Trash(s1.Name)
+
+		_, err := thm.Get(s1.UserID, "guild", ThemeIntro)
+		test.AssertError(t, err, ErrDoesNotExist)
+
+		_ = m.Restore(s1.Name)
+
+		intro, err := thm.Get(s1.UserID, "guild", ThemeIntro)
+		test.AssertError(t, err, nil)
+		test.AssertType(t, intro.Name, s1.Name)
+	})
+}
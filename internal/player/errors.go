@@ -0,0 +1,13 @@
+package player
+
+import (
+	"errors"
+)
+
+var (
+	ErrInterrupted  = errors.New("sound was interrupted by another sound")
+	ErrClosed       = errors.New("player was closed")
+	ErrStopped      = errors.New("sound was stopped")
+	ErrSkipped      = errors.New("sound was skipped")
+	ErrDisconnected = errors.New("voice connection was lost and could not be rejoined")
+)
@@ -0,0 +1,90 @@
+package player
+
+import (
+	"sync"
+	"time"
+
+	"github.com/tweekes0/pal-bot/internal/clock"
+)
+
+// An IdleTimer calls a function once nothing has been played on a voice
+// connection for a while, every play resets it
+type IdleTimer struct {
+	mu      sync.Mutex
+	clock   clock.Clock
+	timeout time.Duration
+	onIdle  func()
+	timer   clock.Timer
+	busy    int // Plays that have started but not finished
+	stopped bool
+}
+
+// Creates an IdleTimer that calls onIdle after timeout has passed without a play,
+// a timeout of zero or less never calls it
+func NewIdleTimer(c clock.Clock, timeout time.Duration, onIdle func()) *IdleTimer {
+	t := &IdleTimer{clock: c, timeout: timeout, onIdle: onIdle}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.schedule()
+
+	return t
+}
+
+// Starts waiting for the timeout again, the caller must hold the lock
+func (t *IdleTimer) schedule() {
+	if t.timer != nil {
+		t.timer.Stop()
+		t.timer = nil
+	}
+
+	if t.stopped || t.busy > 0 || t.timeout <= 0 {
+		return
+	}
+
+	var timer clock.Timer
+	timer = t.clock.AfterFunc(t.timeout, func() {
+		t.mu.Lock()
+		// a reset may have replaced this timer after it was due but before it got the lock
+		if t.timer != timer {
+			t.mu.Unlock()
+			return
+		}
+
+		t.timer = nil
+		t.stopped = true
+		t.mu.Unlock()
+
+		t.onIdle()
+	})
+	t.timer = timer
+}
+
+// Marks the start of a play, the timer waits until it is done
+func (t *IdleTimer) Start() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.busy++
+	t.schedule()
+}
+
+// Marks the end of a play, the timeout starts again once every play is done
+func (t *IdleTimer) Done() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.busy > 0 {
+		t.busy--
+	}
+	t.schedule()
+}
+
+// Stops the timer without calling onIdle
+func (t *IdleTimer) Stop() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.stopped = true
+	t.schedule()
+}
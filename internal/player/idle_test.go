@@ -0,0 +1,85 @@
+package player
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tweekes0/pal-bot/internal/clock"
+	test "github.com/tweekes0/pal-bot/internal/testing"
+)
+
+// Creates an IdleTimer with a fake clock and counts how many times it goes idle
+func idleTestSetup(t *testing.T, timeout time.Duration) (*IdleTimer, *clock.Fake, *int) {
+	t.Parallel()
+
+	c := clock.NewFake(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC))
+	idle := 0
+	timer := NewIdleTimer(c, timeout, func() { idle++ })
+
+	return timer, c, &idle
+}
+
+func TestIdleTimer(t *testing.T) {
+	t.Run("idle after the timeout", func(t *testing.T) {
+		_, c, idle := idleTestSetup(t, 5*time.Minute)
+
+		c.Advance(4 * time.Minute)
+		test.AssertType(t, *idle, 0)
+
+		c.Advance(time.Minute)
+		test.AssertType(t, *idle, 1)
+
+		c.Advance(time.Hour)
+		test.AssertType(t, *idle, 1)
+	})
+
+	t.Run("plays reset the timeout", func(t *testing.T) {
+		timer, c, idle := idleTestSetup(t, 5*time.Minute)
+
+		c.Advance(4 * time.Minute)
+		timer.Start()
+		timer.Done()
+
+		c.Advance(4 * time.Minute)
+		test.AssertType(t, *idle, 0)
+
+		c.Advance(time.Minute)
+		test.AssertType(t, *idle, 1)
+	})
+
+	t.Run("not idle while playing", func(t *testing.T) {
+		timer, c, idle := idleTestSetup(t, 5*time.Minute)
+
+		timer.Start()
+		timer.Start()
+		c.Advance(time.Hour)
+		test.AssertType(t, *idle, 0)
+
+		timer.Done()
+		c.Advance(time.Hour)
+		test.AssertType(t, *idle, 0)
+
+		timer.Done()
+		c.Advance(5 * time.Minute)
+		test.AssertType(t, *idle, 1)
+	})
+
+	t.Run("stopped timer is never idle", func(t *testing.T) {
+		timer, c, idle := idleTestSetup(t, 5*time.Minute)
+
+		timer.Stop()
+		timer.Start()
+		timer.Done()
+
+		c.Advance(time.Hour)
+		test.AssertType(t, *idle, 0)
+		test.AssertType(t, c.Pending(), 0)
+	})
+
+	t.Run("no timeout is never idle", func(t *testing.T) {
+		_, c, idle := idleTestSetup(t, 0)
+
+		c.Advance(time.Hour)
+		test.AssertType(t, *idle, 0)
+	})
+}
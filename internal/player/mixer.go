@@ -0,0 +1,71 @@
+package player
+
+import (
+	"math"
+
+	"github.com/tweekes0/pal-bot/internal/sounds"
+	"layeh.com/gopus"
+)
+
+// Mixes several opus streams into one by decoding them to PCM, summing the
+// samples and encoding the result again
+type mixer struct {
+	encoder *gopus.Encoder
+}
+
+// Creates a mixer that encodes DCA audio
+func newMixer() (*mixer, error) {
+	encoder, err := gopus.NewEncoder(sounds.SAMPLE_RATE, sounds.CHANNELS, gopus.Audio)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mixer{encoder: encoder}, nil
+}
+
+// Encodes the mix of a frame of PCM samples from each stream
+func (m *mixer) mix(frames [][]int16) ([]byte, error) {
+	return m.encoder.Encode(mixPCM(frames), sounds.FRAME_SIZE, sounds.MAX_FRAME_SIZE)
+}
+
+// Sums frames of PCM samples, shorter frames are padded with silence. Frames that
+// would clip are scaled down as a whole so loud mixes keep their shape.
+func mixPCM(frames [][]int16) []int16 {
+	size := 0
+	for _, f := range frames {
+		if len(f) > size {
+			size = len(f)
+		}
+	}
+
+	sum := make([]int32, size)
+	var peak int32
+
+	for _, f := range frames {
+		for i, sample := range f {
+			sum[i] += int32(sample)
+		}
+	}
+
+	for _, s := range sum {
+		if s < 0 {
+			s = -s
+		}
+
+		if s > peak {
+			peak = s
+		}
+	}
+
+	scale := 1.0
+	if peak > math.MaxInt16 {
+		scale = float64(math.MaxInt16) / float64(peak)
+	}
+
+	pcm := make([]int16, size)
+	for i, s := range sum {
+		pcm[i] = int16(float64(s) * scale)
+	}
+
+	return pcm
+}
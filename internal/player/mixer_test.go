@@ -0,0 +1,46 @@
+package player
+
+import (
+	"math"
+	"testing"
+
+	test "github.com/tweekes0/pal-bot/internal/testing"
+)
+
+func TestMixPCM(t *testing.T) {
+	testCases := []struct {
+		description string
+		input       [][]int16
+		expected    []int16
+	}{
+		{
+			description: "sum frames",
+			input:       [][]int16{{1, 2, -3}, {4, -5, 6}},
+			expected:    []int16{5, -3, 3},
+		},
+		{
+			description: "pad shorter frames with silence",
+			input:       [][]int16{{1, 2, 3, 4}, {1, 1}, nil},
+			expected:    []int16{2, 3, 3, 4},
+		},
+		{
+			description: "scale frames that would clip",
+			input:       [][]int16{{math.MaxInt16, 100, -math.MaxInt16}, {math.MaxInt16, 100, 0}},
+			expected:    []int16{math.MaxInt16, 100, -math.MaxInt16 / 2},
+		},
+		{
+			description: "no frames",
+			input:       nil,
+			expected:    []int16{},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			test.AssertType(t, mixPCM(tc.input), tc.expected)
+		})
+	}
+}
@@ -0,0 +1,497 @@
+package player
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tweekes0/pal-bot/internal/sounds"
+	"layeh.com/gopus"
+)
+
+// What a player does with a sound that is played while another is playing
+type Policy string
+
+const (
+	PolicyQueue     Policy = "queue"     // Play it once the sounds before it have finished
+	PolicyInterrupt Policy = "interrupt" // Stop every other sound and play it straight away
+	PolicyMix       Policy = "mix"       // Play it on top of the sounds that are playing
+)
+
+// Parses the name of a policy
+func ParsePolicy(name string) (Policy, bool) {
+	switch p := Policy(strings.ToLower(name)); p {
+	case PolicyQueue, PolicyInterrupt, PolicyMix:
+		return p, true
+	}
+
+	return "", false
+}
+
+// Struct for a sound that is playing or waiting to play
+type track struct {
+	frames   [][]byte
+	pos      int
+	decoder  *gopus.Decoder // Only created once the track is mixed with another
+	done     chan error
+	finished bool
+}
+
+// Lets whoever played the track know it will not play any further
+func (t *track) finish(err error) {
+	if t.finished {
+		return
+	}
+
+	t.finished = true
+	t.done <- err
+}
+
+// Decodes the track's next frame, a frame that cannot be decoded is silent
+func (t *track) decode() []int16 {
+	frame := t.frames[t.pos]
+	t.pos++
+
+	if t.decoder == nil {
+		var err error
+		if t.decoder, err = gopus.NewDecoder(sounds.SAMPLE_RATE, sounds.CHANNELS); err != nil {
+			return nil
+		}
+	}
+
+	pcm, err := t.decoder.Decode(frame, sounds.FRAME_SIZE, false)
+	if err != nil {
+		return nil
+	}
+
+	return pcm
+}
+
+// Struct for where a Player sends its frames, usually a discordgo voice connection
+type Output struct {
+	Send     chan<- []byte    // The connection's OpusSend channel
+	Speaking func(bool) error // Sets the connection's speaking status
+	Ready    func() bool      // Whether the connection can send audio, nil if it always can
+}
+
+// A Player sends the opus frames of sounds to a voice connection one at a time,
+// so several sounds can be played at once without interleaving their frames.
+type Player struct {
+	mu          sync.Mutex
+	out         Output
+	policy      Policy
+	queue       []*track // Tracks waiting for the active tracks to finish
+	active      []*track // Tracks playing now, more than one only when mixing
+	mixer       *mixer
+	paused      bool
+	sendTimeout time.Duration // How long a frame can wait to be sent before onStall is called
+	onStall     func()
+	cleared     int // The number of times every track was removed
+
+	wake      chan struct{}
+	recovered chan struct{} // Signalled by Reconnect and Fail, a stalled Player waits for it
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+// Creates a Player that sends frames to a voice connection,
+// the Player starts with the queue policy
+func New(out Output) *Player {
+	p := newPlayer(out)
+	go p.run()
+
+	return p
+}
+
+// Creates a Player without starting the goroutine that sends its frames
+func newPlayer(out Output) *Player {
+	return &Player{
+		out:       out,
+		policy:    PolicyQueue,
+		wake:      make(chan struct{}, 1),
+		recovered: make(chan struct{}, 1),
+		closed:    make(chan struct{}),
+	}
+}
+
+// Sets a function to call when a frame has waited longer than timeout to be sent, which
+// means the connection is dead. Sending waits until Reconnect or Fail is called.
+func (p *Player) OnStall(timeout time.Duration, f func()) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.sendTimeout = timeout
+	p.onStall = f
+}
+
+// Sends frames to a new connection, the sound that was playing carries on where it stopped
+func (p *Player) Reconnect(out Output) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.out = out
+	p.recover()
+	p.notify()
+}
+
+// Stops every sound that is playing or waiting to play because the connection is lost
+func (p *Player) Fail(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.clear(err)
+	p.recover()
+	p.notify()
+}
+
+// Checks whether the Player's connection can send audio
+func (p *Player) Ready() bool {
+	p.mu.Lock()
+	ready := p.out.Ready
+	p.mu.Unlock()
+
+	return ready == nil || ready()
+}
+
+// Sets what the Player does with sounds played while another is playing
+func (p *Player) SetPolicy(policy Policy) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.policy = policy
+}
+
+// Plays the opus frames of a sound according to the Player's policy. The returned
+// channel receives nil once the sound has played or the reason it did not.
+func (p *Player) Play(frames [][]byte) <-chan error {
+	t := &track{frames: frames, done: make(chan error, 1)}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	select {
+	case <-p.closed:
+		t.finish(ErrClosed)
+		return t.done
+	default:
+	}
+
+	if len(frames) == 0 {
+		t.finish(nil)
+		return t.done
+	}
+
+	switch p.policy {
+	case PolicyInterrupt:
+		p.clear(ErrInterrupted)
+		p.active = append(p.active, t)
+	case PolicyMix:
+		p.active = append(p.active, t)
+	default:
+		p.queue = append(p.queue, t)
+	}
+
+	p.notify()
+
+	return t.done
+}
+
+// Wakes the goroutine sending frames if it is waiting, the caller must hold the lock
+func (p *Player) notify() {
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Lets a stalled Player know the connection was dealt with, the caller must hold the lock
+func (p *Player) recover() {
+	select {
+	case p.recovered <- struct{}{}:
+	default:
+	}
+}
+
+// Checks whether any sound is playing or waiting to play, the caller must hold the lock
+func (p *Player) playing() bool {
+	return len(p.active) > 0 || len(p.queue) > 0
+}
+
+// Gets how many times every track was removed, a frame taken before the
+// count changed belongs to a sound that was stopped
+func (p *Player) generation() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.cleared
+}
+
+// Stops every sound that is playing or waiting to play and resumes the Player if it
+// was paused. Returns false if nothing was playing.
+func (p *Player) Stop() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	playing := p.playing()
+	p.clear(ErrStopped)
+	p.paused = false
+
+	return playing
+}
+
+// Stops the sounds that are playing so the next sound in the queue can play, when
+// mixing every sound is playing so all of them are skipped. Returns false if nothing
+// was playing.
+func (p *Player) Skip() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.playing() {
+		return false
+	}
+
+	// a queued sound is only moved to active once a frame is needed
+	if len(p.active) == 0 {
+		p.active = append(p.active, p.queue[0])
+		p.queue = p.queue[1:]
+	}
+
+	for _, t := range p.active {
+		t.finish(ErrSkipped)
+	}
+	p.active = nil
+	p.notify()
+
+	return true
+}
+
+// Pauses the sounds that are playing or waiting to play until the Player is resumed.
+// Returns false if nothing was playing or the Player was already paused.
+func (p *Player) Pause() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.paused || !p.playing() {
+		return false
+	}
+
+	p.paused = true
+	return true
+}
+
+// Resumes a paused Player. Returns false if the Player was not paused.
+func (p *Player) Resume() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.paused {
+		return false
+	}
+
+	p.paused = false
+	p.notify()
+
+	return true
+}
+
+// Removes every track, the caller must hold the lock
+func (p *Player) clear(err error) {
+	for _, t := range append(p.active, p.queue...) {
+		t.finish(err)
+	}
+
+	p.active = nil
+	p.queue = nil
+	p.cleared++
+}
+
+// Gets the next frame to send and finishes the tracks that have no frames left, along
+// with how many times every track was removed so far. Returns false when nothing is
+// playing or the Player is paused.
+func (p *Player) next() ([]byte, int, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.paused {
+		return nil, p.cleared, false
+	}
+
+	if len(p.active) == 0 && len(p.queue) > 0 {
+		p.active = append(p.active, p.queue[0])
+		p.queue = p.queue[1:]
+	}
+
+	if len(p.active) == 0 {
+		return nil, p.cleared, false
+	}
+
+	var frame []byte
+	if len(p.active) == 1 {
+		t := p.active[0]
+		frame = t.frames[t.pos]
+		t.pos++
+	} else {
+		frame = p.mix()
+	}
+
+	playing := p.active[:0]
+	for _, t := range p.active {
+		if t.pos < len(t.frames) {
+			playing = append(playing, t)
+			continue
+		}
+
+		t.finish(nil)
+	}
+	p.active = playing
+
+	return frame, p.cleared, true
+}
+
+// Mixes the next frame of every active track, the caller must hold the lock
+func (p *Player) mix() []byte {
+	pcm := make([][]int16, len(p.active))
+	for i, t := range p.active {
+		pcm[i] = t.decode()
+	}
+
+	if p.mixer == nil {
+		m, err := newMixer()
+		if err != nil {
+			return p.active[0].frames[p.active[0].pos-1]
+		}
+
+		p.mixer = m
+	}
+
+	frame, err := p.mixer.mix(pcm)
+	if err != nil {
+		// better to drop the other tracks for a frame than to go silent
+		return p.active[0].frames[p.active[0].pos-1]
+	}
+
+	return frame
+}
+
+// Sends frames until the Player is closed, speaking while there is something to send.
+// Frames are taken one at a time so stopping, skipping and pausing apply between frames.
+func (p *Player) run() {
+	speaking := false
+	var frame []byte // Taken from a track but not sent yet
+	var gen int
+
+	for {
+		if frame == nil {
+			var ok bool
+			if frame, gen, ok = p.next(); !ok {
+				if speaking {
+					_ = p.output().Speaking(false)
+					speaking = false
+				}
+
+				select {
+				case <-p.wake:
+					continue
+				case <-p.closed:
+					return
+				}
+			}
+		}
+
+		out := p.output()
+		if !speaking {
+			_ = out.Speaking(true)
+			speaking = true
+		}
+
+		sent, open := p.sendFrame(out.Send, frame)
+		if !open {
+			return
+		}
+
+		if sent {
+			frame = nil
+			continue
+		}
+
+		// a new connection has to start speaking again
+		speaking = false
+		if !p.stall() {
+			return
+		}
+
+		// the sounds were stopped or failed while waiting
+		if p.generation() != gen {
+			frame = nil
+		}
+	}
+}
+
+// Gets where the Player sends its frames
+func (p *Player) output() Output {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.out
+}
+
+// Sends a frame, sent is false if it waited longer than the send timeout
+// and open is false if the Player was closed while waiting
+func (p *Player) sendFrame(send chan<- []byte, frame []byte) (sent, open bool) {
+	p.mu.Lock()
+	timeout := p.sendTimeout
+	p.mu.Unlock()
+
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	select {
+	case send <- frame:
+		return true, true
+	case <-expired:
+		return false, true
+	case <-p.closed:
+		return false, false
+	}
+}
+
+// Lets the stall handler know the connection is dead and waits until it is reconnected
+// or failed, returns false if the Player was closed while waiting
+func (p *Player) stall() bool {
+	p.mu.Lock()
+	onStall := p.onStall
+
+	// only a Reconnect or Fail after the stall means it was dealt with, playing,
+	// skipping or resuming sounds while the connection is dead does not
+	select {
+	case <-p.recovered:
+	default:
+	}
+	p.mu.Unlock()
+
+	if onStall != nil {
+		go onStall()
+	}
+
+	select {
+	case <-p.recovered:
+		return true
+	case <-p.closed:
+		return false
+	}
+}
+
+// Stops the Player, sounds that are playing or waiting to play are finished with ErrClosed
+func (p *Player) Close() {
+	p.closeOnce.Do(func() {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+
+		close(p.closed)
+		p.clear(ErrClosed)
+	})
+}
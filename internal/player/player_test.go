@@ -0,0 +1,427 @@
+package player
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/tweekes0/pal-bot/internal/sounds"
+	test "github.com/tweekes0/pal-bot/internal/testing"
+
+	"layeh.com/gopus"
+)
+
+// Speaking function of an Output that doesn't need to know
+func silent(bool) error {
+	return nil
+}
+
+// Creates a Player that isn't running so frames can be taken with next
+func playerTestSetup(t *testing.T, policy Policy) *Player {
+	t.Parallel()
+
+	p := newPlayer(Output{Send: make(chan []byte), Speaking: silent})
+	p.SetPolicy(policy)
+
+	return p
+}
+
+// Takes every frame from a Player until nothing is playing
+func drain(p *Player) [][]byte {
+	frames := [][]byte{}
+	for {
+		frame, _, ok := p.next()
+		if !ok {
+			return frames
+		}
+
+		frames = append(frames, frame)
+	}
+}
+
+// Encodes frames of a sine wave with the given amplitude
+func encodeSine(t *testing.T, n int, amplitude float64) [][]byte {
+	encoder, err := gopus.NewEncoder(sounds.SAMPLE_RATE, sounds.CHANNELS, gopus.Audio)
+	if err != nil {
+		t.Fatalf("failed to create encoder: %v", err)
+	}
+
+	frames := make([][]byte, n)
+	for i := range frames {
+		pcm := make([]int16, sounds.FRAME_SIZE*sounds.CHANNELS)
+		for j := 0; j < sounds.FRAME_SIZE; j++ {
+			sample := int16(amplitude * math.Sin(2*math.Pi*440*float64(i*sounds.FRAME_SIZE+j)/sounds.SAMPLE_RATE))
+			pcm[j*2], pcm[j*2+1] = sample, sample
+		}
+
+		frames[i], err = encoder.Encode(pcm, sounds.FRAME_SIZE, sounds.MAX_FRAME_SIZE)
+		if err != nil {
+			t.Fatalf("failed to encode frame: %v", err)
+		}
+	}
+
+	return frames
+}
+
+// Gets the root mean square of the decoded frames, skipping the first few while the codec settles
+func rms(t *testing.T, frames [][]byte) float64 {
+	decoder, err := gopus.NewDecoder(sounds.SAMPLE_RATE, sounds.CHANNELS)
+	if err != nil {
+		t.Fatalf("failed to create decoder: %v", err)
+	}
+
+	var sum float64
+	var count int
+	for i, frame := range frames {
+		pcm, err := decoder.Decode(frame, sounds.FRAME_SIZE, false)
+		if err != nil {
+			t.Fatalf("failed to decode frame: %v", err)
+		}
+
+		if i < 5 {
+			continue
+		}
+
+		for _, s := range pcm {
+			sum += float64(s) * float64(s)
+			count++
+		}
+	}
+
+	return math.Sqrt(sum / float64(count))
+}
+
+func TestParsePolicy(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected Policy
+		ok       bool
+	}{
+		{"queue", PolicyQueue, true},
+		{"Interrupt", PolicyInterrupt, true},
+		{"MIX", PolicyMix, true},
+		{"shuffle", "", false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.input, func(t *testing.T) {
+			t.Parallel()
+
+			policy, ok := ParsePolicy(tc.input)
+			test.AssertType(t, policy, tc.expected)
+			test.AssertType(t, ok, tc.ok)
+		})
+	}
+}
+
+func TestPlay(t *testing.T) {
+	t.Run("queue plays sounds in order", func(t *testing.T) {
+		p := playerTestSetup(t, PolicyQueue)
+
+		first := p.Play([][]byte{{1}, {2}})
+		second := p.Play([][]byte{{3}})
+
+		test.AssertType(t, drain(p), [][]byte{{1}, {2}, {3}})
+		test.AssertError(t, <-first, nil)
+		test.AssertError(t, <-second, nil)
+	})
+
+	t.Run("interrupt stops sounds that are playing", func(t *testing.T) {
+		p := playerTestSetup(t, PolicyInterrupt)
+
+		first := p.Play([][]byte{{1}, {2}, {3}})
+		frame, _, _ := p.next()
+		test.AssertType(t, frame, []byte{1})
+
+		second := p.Play([][]byte{{4}, {5}})
+		test.AssertError(t, <-first, ErrInterrupted)
+
+		test.AssertType(t, drain(p), [][]byte{{4}, {5}})
+		test.AssertError(t, <-second, nil)
+	})
+
+	t.Run("mix plays sounds at the same time", func(t *testing.T) {
+		p := playerTestSetup(t, PolicyMix)
+		sine := encodeSine(t, 20, 8000)
+
+		first := p.Play(sine)
+		second := p.Play(sine[:10])
+
+		frames := drain(p)
+		test.AssertType(t, len(frames), 20)
+		test.AssertError(t, <-first, nil)
+		test.AssertError(t, <-second, nil)
+
+		// the same sound played twice should be about twice as loud
+		ratio := rms(t, frames[:10]) / rms(t, sine[:10])
+		if ratio < 1.8 || ratio > 2.2 {
+			t.Fatalf("got: %v, expected: a mix about twice as loud", ratio)
+		}
+	})
+
+	t.Run("empty sounds finish straight away", func(t *testing.T) {
+		p := playerTestSetup(t, PolicyQueue)
+
+		test.AssertError(t, <-p.Play(nil), nil)
+		test.AssertType(t, drain(p), [][]byte{})
+	})
+
+	t.Run("closed player does not play", func(t *testing.T) {
+		p := playerTestSetup(t, PolicyQueue)
+
+		playing := p.Play([][]byte{{1}})
+		p.Close()
+
+		test.AssertError(t, <-playing, ErrClosed)
+		test.AssertError(t, <-p.Play([][]byte{{2}}), ErrClosed)
+		test.AssertType(t, drain(p), [][]byte{})
+	})
+}
+
+func TestRun(t *testing.T) {
+	t.Run("send frames while speaking", func(t *testing.T) {
+		t.Parallel()
+
+		send := make(chan []byte)
+		speaking := make(chan bool, 2)
+		p := New(Output{Send: send, Speaking: func(b bool) error {
+			speaking <- b
+			return nil
+		}})
+		defer p.Close()
+
+		done := p.Play([][]byte{{1}, {2}})
+
+		test.AssertType(t, <-send, []byte{1})
+		test.AssertType(t, <-speaking, true)
+		test.AssertType(t, <-send, []byte{2})
+		test.AssertError(t, <-done, nil)
+
+		select {
+		case b := <-speaking:
+			test.AssertType(t, b, false)
+		case <-time.After(time.Second):
+			t.Fatal("player did not stop speaking")
+		}
+	})
+}
+
+func TestControls(t *testing.T) {
+	t.Run("stop every sound", func(t *testing.T) {
+		p := playerTestSetup(t, PolicyQueue)
+
+		first := p.Play([][]byte{{1}, {2}})
+		second := p.Play([][]byte{{3}})
+		p.next()
+
+		test.AssertType(t, p.Stop(), true)
+		test.AssertError(t, <-first, ErrStopped)
+		test.AssertError(t, <-second, ErrStopped)
+		test.AssertType(t, drain(p), [][]byte{})
+		test.AssertType(t, p.Stop(), false)
+	})
+
+	t.Run("skip to the next sound", func(t *testing.T) {
+		p := playerTestSetup(t, PolicyQueue)
+
+		first := p.Play([][]byte{{1}, {2}})
+		second := p.Play([][]byte{{3}})
+		p.next()
+
+		test.AssertType(t, p.Skip(), true)
+		test.AssertError(t, <-first, ErrSkipped)
+		test.AssertType(t, drain(p), [][]byte{{3}})
+		test.AssertError(t, <-second, nil)
+		test.AssertType(t, p.Skip(), false)
+	})
+
+	t.Run("skip a sound that has not started", func(t *testing.T) {
+		p := playerTestSetup(t, PolicyQueue)
+
+		first := p.Play([][]byte{{1}})
+		second := p.Play([][]byte{{2}})
+
+		test.AssertType(t, p.Skip(), true)
+		test.AssertError(t, <-first, ErrSkipped)
+		test.AssertType(t, drain(p), [][]byte{{2}})
+		test.AssertError(t, <-second, nil)
+	})
+
+	t.Run("pause and resume", func(t *testing.T) {
+		p := playerTestSetup(t, PolicyQueue)
+
+		test.AssertType(t, p.Pause(), false)
+
+		done := p.Play([][]byte{{1}, {2}})
+		p.next()
+
+		test.AssertType(t, p.Pause(), true)
+		test.AssertType(t, p.Pause(), false)
+		test.AssertType(t, drain(p), [][]byte{})
+
+		test.AssertType(t, p.Resume(), true)
+		test.AssertType(t, p.Resume(), false)
+		test.AssertType(t, drain(p), [][]byte{{2}})
+		test.AssertError(t, <-done, nil)
+	})
+
+	t.Run("stop resumes a paused player", func(t *testing.T) {
+		p := playerTestSetup(t, PolicyQueue)
+
+		p.Play([][]byte{{1}})
+		p.Pause()
+		p.Stop()
+
+		done := p.Play([][]byte{{2}})
+		test.AssertType(t, drain(p), [][]byte{{2}})
+		test.AssertError(t, <-done, nil)
+	})
+}
+
+// Receives a frame from a fake OpusSend channel, failing if none is sent in time
+func receive(t *testing.T, send <-chan []byte) []byte {
+	t.Helper()
+
+	select {
+	case frame := <-send:
+		return frame
+	case <-time.After(time.Second):
+		t.Fatal("player did not send a frame")
+	}
+
+	return nil
+}
+
+func TestRunControls(t *testing.T) {
+	t.Run("paused player sends nothing until resumed", func(t *testing.T) {
+		t.Parallel()
+
+		send := make(chan []byte)
+		p := newPlayer(Output{Send: send, Speaking: silent})
+		defer p.Close()
+
+		done := p.Play([][]byte{{1}, {2}})
+		p.Pause()
+		go p.run()
+
+		select {
+		case frame := <-send:
+			t.Fatalf("got: %v, expected: no frames while paused", frame)
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		p.Resume()
+		test.AssertType(t, receive(t, send), []byte{1})
+		test.AssertType(t, receive(t, send), []byte{2})
+		test.AssertError(t, <-done, nil)
+	})
+
+	t.Run("stopped player stops speaking", func(t *testing.T) {
+		t.Parallel()
+
+		send := make(chan []byte)
+		speaking := make(chan bool, 2)
+		p := New(Output{Send: send, Speaking: func(b bool) error {
+			speaking <- b
+			return nil
+		}})
+		defer p.Close()
+
+		done := p.Play(make([][]byte, 100))
+		receive(t, send)
+		test.AssertType(t, <-speaking, true)
+
+		p.Stop()
+		test.AssertError(t, <-done, ErrStopped)
+
+		// the frame taken before stopping may still be waiting to be sent
+		select {
+		case <-send:
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		select {
+		case b := <-speaking:
+			test.AssertType(t, b, false)
+		case <-time.After(time.Second):
+			t.Fatal("player did not stop speaking")
+		}
+	})
+}
+
+func TestStall(t *testing.T) {
+	t.Run("carry on after reconnecting", func(t *testing.T) {
+		t.Parallel()
+
+		stalled := make(chan struct{}, 1)
+		p := New(Output{Send: make(chan []byte), Speaking: silent})
+		p.OnStall(10*time.Millisecond, func() { stalled <- struct{}{} })
+		defer p.Close()
+
+		done := p.Play([][]byte{{1}, {2}})
+		<-stalled
+
+		send := make(chan []byte)
+		p.Reconnect(Output{Send: send, Speaking: silent})
+
+		test.AssertType(t, receive(t, send), []byte{1})
+		test.AssertType(t, receive(t, send), []byte{2})
+		test.AssertError(t, <-done, nil)
+	})
+
+	t.Run("wait for the connection while stalled", func(t *testing.T) {
+		t.Parallel()
+
+		stalled := make(chan struct{}, 1)
+		p := New(Output{Send: make(chan []byte), Speaking: silent})
+		p.OnStall(10*time.Millisecond, func() { stalled <- struct{}{} })
+		defer p.Close()
+
+		done := p.Play([][]byte{{1}})
+		<-stalled
+
+		// playing, skipping and resuming don't wake a stalled player
+		second := p.Play([][]byte{{2}})
+		p.Resume()
+
+		select {
+		case <-stalled:
+			t.Fatal("player stalled again before it was reconnected")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		send := make(chan []byte)
+		p.Reconnect(Output{Send: send, Speaking: silent})
+
+		test.AssertType(t, receive(t, send), []byte{1})
+		test.AssertError(t, <-done, nil)
+		test.AssertType(t, receive(t, send), []byte{2})
+		test.AssertError(t, <-second, nil)
+	})
+
+	t.Run("fail sounds when the connection is lost", func(t *testing.T) {
+		t.Parallel()
+
+		stalled := make(chan struct{}, 1)
+		p := New(Output{Send: make(chan []byte), Speaking: silent})
+		p.OnStall(10*time.Millisecond, func() { stalled <- struct{}{} })
+		defer p.Close()
+
+		first := p.Play([][]byte{{1}, {2}})
+		second := p.Play([][]byte{{3}})
+		<-stalled
+
+		p.Fail(ErrDisconnected)
+		test.AssertError(t, <-first, ErrDisconnected)
+		test.AssertError(t, <-second, ErrDisconnected)
+
+		// the frame that was waiting belonged to a failed sound so it is not sent
+		send := make(chan []byte)
+		p.Reconnect(Output{Send: send, Speaking: silent})
+		done := p.Play([][]byte{{4}})
+
+		test.AssertType(t, receive(t, send), []byte{4})
+		test.AssertError(t, <-done, nil)
+	})
+}
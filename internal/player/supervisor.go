@@ -0,0 +1,147 @@
+package player
+
+import (
+	"sync"
+	"time"
+
+	"github.com/tweekes0/pal-bot/internal/clock"
+)
+
+// Struct for how a Supervisor watches a voice connection
+type SupervisorConfig struct {
+	Interval    time.Duration // How often the connection is checked
+	SendTimeout time.Duration // How long a frame can wait to be sent before the connection is rejoined
+	Attempts    int           // How many times to try rejoining before giving up
+	Backoff     time.Duration // How long to wait before the second attempt, doubled before each one after it
+}
+
+// A Supervisor watches the voice connection of a Player and rejoins it when it is not
+// ready on two checks in a row or stops taking frames. Sounds carry on where they stopped
+// once the connection is rejoined, if it can't be they fail with ErrDisconnected.
+type Supervisor struct {
+	mu           sync.Mutex
+	clock        clock.Clock
+	player       *Player
+	cfg          SupervisorConfig
+	rejoin       func() (Output, error)
+	onLost       func(error)
+	timer        clock.Timer
+	retry        clock.Timer // The next rejoin attempt, nil if none is waiting
+	notReady     int         // Checks in a row the connection was not ready
+	reconnecting bool
+	stopped      bool
+}
+
+// Creates a Supervisor for a Player. rejoin joins the voice channel again and onLost is
+// called with the last error from rejoin once the Supervisor has given up.
+func NewSupervisor(c clock.Clock, p *Player, cfg SupervisorConfig, rejoin func() (Output, error), onLost func(error)) *Supervisor {
+	s := &Supervisor{clock: c, player: p, cfg: cfg, rejoin: rejoin, onLost: onLost}
+	p.OnStall(cfg.SendTimeout, s.Reconnect)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.schedule()
+
+	return s
+}
+
+// Waits for the next check, the caller must hold the lock
+func (s *Supervisor) schedule() {
+	if s.stopped || s.cfg.Interval <= 0 {
+		return
+	}
+
+	s.timer = s.clock.AfterFunc(s.cfg.Interval, s.check)
+}
+
+// Rejoins the connection if it has not been ready for two checks in a row
+func (s *Supervisor) check() {
+	ready := s.player.Ready()
+
+	s.mu.Lock()
+	if s.stopped {
+		s.mu.Unlock()
+		return
+	}
+
+	if ready || s.reconnecting {
+		s.notReady = 0
+	} else {
+		s.notReady++
+	}
+
+	// the first miss is left for discordgo's own reconnect
+	dead := s.notReady >= 2
+	s.schedule()
+	s.mu.Unlock()
+
+	if dead {
+		s.Reconnect()
+	}
+}
+
+// Rejoins the connection, giving up after the configured number of attempts. Attempts after
+// the first wait for the backoff so a voice server that is down is not hammered.
+func (s *Supervisor) Reconnect() {
+	s.mu.Lock()
+	if s.stopped || s.reconnecting {
+		s.mu.Unlock()
+		return
+	}
+	s.reconnecting = true
+	s.mu.Unlock()
+
+	s.attempt(1)
+}
+
+// Tries to rejoin the connection, scheduling the next attempt if it fails
+func (s *Supervisor) attempt(n int) {
+	out, err := s.rejoin()
+	if err == nil {
+		s.player.Reconnect(out)
+	}
+
+	s.mu.Lock()
+	s.retry = nil
+
+	if err != nil && !s.stopped && n < s.cfg.Attempts {
+		delay := s.cfg.Backoff << (n - 1)
+		if delay > 0 {
+			s.retry = s.clock.AfterFunc(delay, func() { s.attempt(n + 1) })
+			s.mu.Unlock()
+			return
+		}
+
+		s.mu.Unlock()
+		s.attempt(n + 1)
+		return
+	}
+
+	s.reconnecting = false
+	s.notReady = 0
+	stopped := s.stopped
+	s.mu.Unlock()
+
+	if err != nil && !stopped {
+		s.Stop()
+		s.player.Fail(ErrDisconnected)
+		s.onLost(err)
+	}
+}
+
+// Stops watching the connection
+func (s *Supervisor) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.stopped = true
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+
+	if s.retry != nil {
+		s.retry.Stop()
+		s.retry = nil
+	}
+}
@@ -0,0 +1,154 @@
+package player
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tweekes0/pal-bot/internal/clock"
+	test "github.com/tweekes0/pal-bot/internal/testing"
+)
+
+var errRejoin = errors.New("could not rejoin")
+
+// Fake voice connection that can be marked as dead and counts the times it is rejoined
+type fakeConnection struct {
+	mu      sync.Mutex
+	ready   bool
+	rejoins int
+	fails   int // The number of rejoins that fail before one works
+	lost    error
+}
+
+func (c *fakeConnection) isReady() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.ready
+}
+
+func (c *fakeConnection) setReady(ready bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.ready = ready
+}
+
+func (c *fakeConnection) rejoin() (Output, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.rejoins++
+	if c.rejoins <= c.fails {
+		return Output{}, errRejoin
+	}
+
+	c.ready = true
+	return Output{Send: make(chan []byte), Speaking: silent, Ready: c.isReady}, nil
+}
+
+// Creates a Supervisor checking a fake connection every second with a fake clock
+func supervisorTestSetup(t *testing.T, fails int) (*Supervisor, *Player, *fakeConnection, *clock.Fake) {
+	t.Parallel()
+
+	conn := &fakeConnection{ready: true, fails: fails}
+	c := clock.NewFake(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC))
+	p := newPlayer(Output{Send: make(chan []byte), Speaking: silent, Ready: conn.isReady})
+
+	cfg := SupervisorConfig{Interval: time.Second, Attempts: 3}
+	s := NewSupervisor(c, p, cfg, conn.rejoin, func(err error) { conn.lost = err })
+
+	return s, p, conn, c
+}
+
+func TestSupervisor(t *testing.T) {
+	t.Run("ready connection is left alone", func(t *testing.T) {
+		_, _, conn, c := supervisorTestSetup(t, 0)
+
+		c.Advance(time.Minute)
+		test.AssertType(t, conn.rejoins, 0)
+	})
+
+	t.Run("rejoin after two checks that are not ready", func(t *testing.T) {
+		_, p, conn, c := supervisorTestSetup(t, 0)
+
+		conn.setReady(false)
+		c.Advance(time.Second)
+		test.AssertType(t, conn.rejoins, 0)
+
+		c.Advance(time.Second)
+		test.AssertType(t, conn.rejoins, 1)
+		test.AssertType(t, p.Ready(), true)
+
+		c.Advance(time.Minute)
+		test.AssertType(t, conn.rejoins, 1)
+	})
+
+	t.Run("retry rejoining", func(t *testing.T) {
+		s, _, conn, _ := supervisorTestSetup(t, 2)
+
+		s.Reconnect()
+		test.AssertType(t, conn.rejoins, 3)
+		test.AssertError(t, conn.lost, nil)
+	})
+
+	t.Run("wait longer between each attempt", func(t *testing.T) {
+		s, _, conn, c := supervisorTestSetup(t, 3)
+		s.cfg.Backoff = time.Second
+
+		s.Reconnect()
+		test.AssertType(t, conn.rejoins, 1)
+
+		c.Advance(time.Second)
+		test.AssertType(t, conn.rejoins, 2)
+
+		c.Advance(time.Second)
+		test.AssertType(t, conn.rejoins, 2)
+		test.AssertError(t, conn.lost, nil)
+
+		c.Advance(time.Second)
+		test.AssertType(t, conn.rejoins, 3)
+		test.AssertError(t, conn.lost, errRejoin)
+	})
+
+	t.Run("stopped supervisor stops retrying", func(t *testing.T) {
+		s, _, conn, c := supervisorTestSetup(t, 3)
+		s.cfg.Backoff = time.Second
+
+		s.Reconnect()
+		s.Stop()
+
+		c.Advance(time.Minute)
+		test.AssertType(t, conn.rejoins, 1)
+		test.AssertError(t, conn.lost, nil)
+		test.AssertType(t, c.Pending(), 0)
+	})
+
+	t.Run("give up after every attempt fails", func(t *testing.T) {
+		s, p, conn, c := supervisorTestSetup(t, 3)
+
+		done := p.Play([][]byte{{1}})
+		s.Reconnect()
+
+		test.AssertType(t, conn.rejoins, 3)
+		test.AssertError(t, conn.lost, errRejoin)
+		test.AssertError(t, <-done, ErrDisconnected)
+
+		// a supervisor that gave up stops checking
+		c.Advance(time.Minute)
+		test.AssertType(t, conn.rejoins, 3)
+		test.AssertType(t, c.Pending(), 0)
+	})
+
+	t.Run("stopped supervisor does not rejoin", func(t *testing.T) {
+		s, _, conn, c := supervisorTestSetup(t, 0)
+
+		s.Stop()
+		conn.setReady(false)
+		c.Advance(time.Minute)
+		s.Reconnect()
+
+		test.AssertType(t, conn.rejoins, 0)
+	})
+}
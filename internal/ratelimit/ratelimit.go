@@ -0,0 +1,87 @@
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+const (
+	// Buckets are only pruned once there are more than this many of them
+	PRUNE_THRESHOLD = 1024
+)
+
+// Struct for the tokens left for a single key
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// A token bucket rate limiter. Each key gets its own bucket that holds up to
+// burst tokens and regains one token every interval.
+type Limiter struct {
+	mu       sync.Mutex
+	burst    float64
+	interval time.Duration
+	buckets  map[string]*bucket
+	now      func() time.Time
+}
+
+// Creates a Limiter, a burst or interval of zero disables rate limiting
+func New(burst int, interval time.Duration) *Limiter {
+	return &Limiter{
+		burst:    float64(burst),
+		interval: interval,
+		buckets:  make(map[string]*bucket),
+		now:      time.Now,
+	}
+}
+
+// Checks whether the key has a token left and takes it. If it does not the
+// time until the next token is available is returned.
+func (l *Limiter) Allow(key string) (bool, time.Duration) {
+	if l == nil || l.burst <= 0 || l.interval <= 0 {
+		return true, 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if len(l.buckets) > PRUNE_THRESHOLD {
+		l.prune(now)
+	}
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.burst, updated: now}
+		l.buckets[key] = b
+	}
+
+	l.refill(b, now)
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+
+	wait := time.Duration((1 - b.tokens) * float64(l.interval))
+	return false, wait
+}
+
+// Adds the tokens regained since the bucket was last updated
+func (l *Limiter) refill(b *bucket, now time.Time) {
+	elapsed := now.Sub(b.updated)
+	b.tokens = math.Min(l.burst, b.tokens+float64(elapsed)/float64(l.interval))
+	b.updated = now
+}
+
+// Removes buckets that have refilled, they are the same as a new bucket
+func (l *Limiter) prune(now time.Time) {
+	for key, b := range l.buckets {
+		l.refill(b, now)
+		if b.tokens >= l.burst {
+			delete(l.buckets, key)
+		}
+	}
+}
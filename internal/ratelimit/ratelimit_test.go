@@ -0,0 +1,106 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	test "github.com/tweekes0/pal-bot/internal/testing"
+)
+
+// Creates a Limiter with a clock that only moves when the returned function is called
+func limiterTestSetup(t *testing.T, burst int, interval time.Duration) (*Limiter, func(time.Duration)) {
+	t.Parallel()
+
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := New(burst, interval)
+	l.now = func() time.Time { return now }
+
+	advance := func(d time.Duration) {
+		now = now.Add(d)
+	}
+
+	return l, advance
+}
+
+func TestAllow(t *testing.T) {
+	t.Run("allow up to burst", func(t *testing.T) {
+		l, _ := limiterTestSetup(t, 3, 10*time.Second)
+
+		for i := 0; i < 3; i++ {
+			ok, wait := l.Allow("user")
+			test.AssertType(t, ok, true)
+			test.AssertType(t, wait, time.Duration(0))
+		}
+
+		ok, wait := l.Allow("user")
+		test.AssertType(t, ok, false)
+		test.AssertType(t, wait, 10*time.Second)
+	})
+
+	t.Run("allow after refill", func(t *testing.T) {
+		l, advance := limiterTestSetup(t, 1, 10*time.Second)
+
+		ok, _ := l.Allow("user")
+		test.AssertType(t, ok, true)
+
+		advance(4 * time.Second)
+		ok, wait := l.Allow("user")
+		test.AssertType(t, ok, false)
+		test.AssertType(t, wait, 6*time.Second)
+
+		advance(6 * time.Second)
+		ok, _ = l.Allow("user")
+		test.AssertType(t, ok, true)
+	})
+
+	t.Run("refill does not exceed burst", func(t *testing.T) {
+		l, advance := limiterTestSetup(t, 2, time.Second)
+
+		advance(time.Hour)
+		for i := 0; i < 2; i++ {
+			ok, _ := l.Allow("user")
+			test.AssertType(t, ok, true)
+		}
+
+		ok, _ := l.Allow("user")
+		test.AssertType(t, ok, false)
+	})
+
+	t.Run("keys have separate buckets", func(t *testing.T) {
+		l, _ := limiterTestSetup(t, 1, time.Minute)
+
+		ok, _ := l.Allow("user1")
+		test.AssertType(t, ok, true)
+
+		ok, _ = l.Allow("user1")
+		test.AssertType(t, ok, false)
+
+		ok, _ = l.Allow("user2")
+		test.AssertType(t, ok, true)
+	})
+
+	t.Run("disabled limiter always allows", func(t *testing.T) {
+		l, _ := limiterTestSetup(t, 0, 0)
+
+		for i := 0; i < 100; i++ {
+			ok, _ := l.Allow("user")
+			test.AssertType(t, ok, true)
+		}
+
+		var nl *Limiter
+		ok, _ := nl.Allow("user")
+		test.AssertType(t, ok, true)
+	})
+
+	t.Run("prune refilled buckets", func(t *testing.T) {
+		l, advance := limiterTestSetup(t, 1, time.Second)
+
+		for i := 0; i <= PRUNE_THRESHOLD; i++ {
+			l.Allow(time.Duration(i).String())
+		}
+
+		advance(time.Second)
+		l.Allow("user")
+		test.AssertType(t, len(l.buckets), 1)
+	})
+}
@@ -0,0 +1,10 @@
+package scheduler
+
+import (
+	"errors"
+)
+
+var (
+	ErrInvalidTime       = errors.New("time is not in the 'in 10m', '18:00' or 'every friday 17:00' format")
+	ErrInvalidRecurrence = errors.New("recurrence is not in the 'every friday 17:00' format")
+)
@@ -0,0 +1,121 @@
+package scheduler
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/tweekes0/pal-bot/internal/clock"
+)
+
+// A Scheduler runs jobs by ID at the time they are due. It waits on a single timer
+// for the job that is due first, so the clock it is given decides when jobs run.
+type Scheduler struct {
+	mu      sync.Mutex
+	clock   clock.Clock
+	run     func(id int)
+	jobs    map[int]time.Time
+	timer   clock.Timer
+	stopped bool
+}
+
+// Creates a Scheduler that calls run with the ID of each job once it is due
+func New(c clock.Clock, run func(id int)) *Scheduler {
+	return &Scheduler{clock: c, run: run, jobs: map[int]time.Time{}}
+}
+
+// Adds a job or moves it to a new time, jobs that are already due run straight away
+func (s *Scheduler) Add(id int, at time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.jobs[id] = at
+	s.schedule()
+}
+
+// Removes a job so it doesn't run
+func (s *Scheduler) Remove(id int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.jobs, id)
+	s.schedule()
+}
+
+// Gets the number of jobs waiting to run
+func (s *Scheduler) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.jobs)
+}
+
+// Stops the Scheduler, no more jobs run
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.stopped = true
+	s.schedule()
+}
+
+// Waits for the job that is due first, the caller must hold the lock
+func (s *Scheduler) schedule() {
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+
+	if s.stopped || len(s.jobs) == 0 {
+		return
+	}
+
+	var first time.Time
+	for _, at := range s.jobs {
+		if first.IsZero() || at.Before(first) {
+			first = at
+		}
+	}
+
+	wait := first.Sub(s.clock.Now())
+	if wait < 0 {
+		wait = 0
+	}
+
+	s.timer = s.clock.AfterFunc(wait, s.tick)
+}
+
+// Runs every job that is due, a job has to be added again to run again
+func (s *Scheduler) tick() {
+	s.mu.Lock()
+	if s.stopped {
+		s.mu.Unlock()
+		return
+	}
+
+	now := s.clock.Now()
+	due := []int{}
+	for id, at := range s.jobs {
+		if !at.After(now) {
+			due = append(due, id)
+		}
+	}
+
+	// jobs run in the order they were due
+	sort.Slice(due, func(i, j int) bool {
+		a, b := s.jobs[due[i]], s.jobs[due[j]]
+		return a.Before(b) || (a.Equal(b) && due[i] < due[j])
+	})
+
+	for _, id := range due {
+		delete(s.jobs, id)
+	}
+
+	s.timer = nil
+	s.schedule()
+	s.mu.Unlock()
+
+	for _, id := range due {
+		s.run(id)
+	}
+}
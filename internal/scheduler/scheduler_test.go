@@ -0,0 +1,99 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tweekes0/pal-bot/internal/clock"
+	test "github.com/tweekes0/pal-bot/internal/testing"
+)
+
+// Creates a Scheduler with a fake clock that records the jobs it runs
+func schedulerTestSetup(t *testing.T) (*Scheduler, *clock.Fake, *[]int) {
+	t.Parallel()
+
+	c := clock.NewFake(now)
+	ran := []int{}
+	s := New(c, func(id int) { ran = append(ran, id) })
+
+	return s, c, &ran
+}
+
+func TestScheduler(t *testing.T) {
+	t.Run("run jobs when they are due", func(t *testing.T) {
+		s, c, ran := schedulerTestSetup(t)
+
+		s.Add(1, now.Add(time.Hour))
+		s.Add(2, now.Add(time.Minute))
+		s.Add(3, now.Add(time.Minute))
+
+		c.Advance(59 * time.Second)
+		test.AssertType(t, *ran, []int{})
+
+		c.Advance(time.Second)
+		test.AssertType(t, *ran, []int{2, 3})
+
+		c.Advance(time.Hour)
+		test.AssertType(t, *ran, []int{2, 3, 1})
+		test.AssertType(t, s.Len(), 0)
+	})
+
+	t.Run("run jobs that are already due", func(t *testing.T) {
+		s, c, ran := schedulerTestSetup(t)
+
+		s.Add(1, now.Add(-time.Hour))
+		c.Advance(0)
+		test.AssertType(t, *ran, []int{1})
+	})
+
+	t.Run("move a job", func(t *testing.T) {
+		s, c, ran := schedulerTestSetup(t)
+
+		s.Add(1, now.Add(time.Minute))
+		s.Add(1, now.Add(time.Hour))
+
+		c.Advance(time.Minute)
+		test.AssertType(t, *ran, []int{})
+
+		c.Advance(time.Hour)
+		test.AssertType(t, *ran, []int{1})
+	})
+
+	t.Run("jobs added again while running recur", func(t *testing.T) {
+		t.Parallel()
+
+		c := clock.NewFake(now)
+		runs := 0
+		var s *Scheduler
+		s = New(c, func(id int) {
+			runs++
+			s.Add(id, c.Now().Add(time.Hour))
+		})
+
+		s.Add(1, now.Add(time.Hour))
+		c.Advance(3 * time.Hour)
+		test.AssertType(t, runs, 3)
+	})
+
+	t.Run("removed jobs do not run", func(t *testing.T) {
+		s, c, ran := schedulerTestSetup(t)
+
+		s.Add(1, now.Add(time.Minute))
+		s.Add(2, now.Add(time.Minute))
+		s.Remove(1)
+
+		c.Advance(time.Hour)
+		test.AssertType(t, *ran, []int{2})
+	})
+
+	t.Run("stopped scheduler does not run jobs", func(t *testing.T) {
+		s, c, ran := schedulerTestSetup(t)
+
+		s.Add(1, now.Add(time.Minute))
+		s.Stop()
+
+		c.Advance(time.Hour)
+		test.AssertType(t, *ran, []int{})
+		test.AssertType(t, c.Pending(), 0)
+	})
+}
@@ -0,0 +1,125 @@
+package scheduler
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	CLOCK_LAYOUT = "15:04" // Layout of the time of day in schedules
+	EVERY_DAY    = "day"   // Recurrence day of schedules that run every day
+)
+
+// Struct for a schedule that runs at the same time every day or every week
+type Recurrence struct {
+	Daily   bool
+	Weekday time.Weekday // Only used when not Daily
+	Hour    int
+	Minute  int
+}
+
+// Gets the recurrence in the format it is parsed from, such as 'every friday 17:00'
+func (r Recurrence) String() string {
+	day := EVERY_DAY
+	if !r.Daily {
+		day = strings.ToLower(r.Weekday.String())
+	}
+
+	return fmt.Sprintf("every %v %02d:%02d", day, r.Hour, r.Minute)
+}
+
+// Gets the first time the recurrence runs after the given time, in the given time's location
+func (r Recurrence) Next(after time.Time) time.Time {
+	next := time.Date(after.Year(), after.Month(), after.Day(), r.Hour, r.Minute, 0, 0, after.Location())
+
+	for !next.After(after) || (!r.Daily && next.Weekday() != r.Weekday) {
+		next = next.AddDate(0, 0, 1)
+	}
+
+	return next
+}
+
+// Parses a recurrence such as 'every friday 17:00' or 'every day 09:30'
+func ParseRecurrence(s string) (Recurrence, error) {
+	fields := strings.Fields(strings.ToLower(s))
+	if len(fields) != 3 || fields[0] != "every" {
+		return Recurrence{}, ErrInvalidRecurrence
+	}
+
+	hour, minute, err := parseClock(fields[2])
+	if err != nil {
+		return Recurrence{}, ErrInvalidRecurrence
+	}
+
+	r := Recurrence{Hour: hour, Minute: minute}
+	if fields[1] == EVERY_DAY {
+		r.Daily = true
+		return r, nil
+	}
+
+	day, ok := parseWeekday(fields[1])
+	if !ok {
+		return Recurrence{}, ErrInvalidRecurrence
+	}
+	r.Weekday = day
+
+	return r, nil
+}
+
+// Parses when a schedule should run. It can be a delay such as 'in 10m', a time of day
+// such as '18:00' that runs the next time it comes round, or a recurrence such as
+// 'every friday 17:00'. Returns the first run after now and the recurrence, if any.
+func Parse(when string, now time.Time) (time.Time, *Recurrence, error) {
+	fields := strings.Fields(strings.ToLower(when))
+
+	switch {
+	case len(fields) == 2 && fields[0] == "in":
+		d, err := time.ParseDuration(fields[1])
+		if err != nil || d <= 0 {
+			return time.Time{}, nil, ErrInvalidTime
+		}
+
+		return now.Add(d), nil, nil
+	case len(fields) == 1:
+		hour, minute, err := parseClock(fields[0])
+		if err != nil {
+			return time.Time{}, nil, ErrInvalidTime
+		}
+
+		// a time of day is a daily recurrence that only runs once
+		r := Recurrence{Daily: true, Hour: hour, Minute: minute}
+		return r.Next(now), nil, nil
+	case len(fields) > 0 && fields[0] == "every":
+		r, err := ParseRecurrence(when)
+		if err != nil {
+			return time.Time{}, nil, ErrInvalidTime
+		}
+
+		return r.Next(now), &r, nil
+	}
+
+	return time.Time{}, nil, ErrInvalidTime
+}
+
+// Parses a time of day such as '17:00'
+func parseClock(s string) (int, int, error) {
+	t, err := time.Parse(CLOCK_LAYOUT, s)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return t.Hour(), t.Minute(), nil
+}
+
+// Parses the name of a weekday, full or abbreviated
+func parseWeekday(s string) (time.Weekday, bool) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		name := strings.ToLower(d.String())
+		if s == name || (len(s) >= 3 && strings.HasPrefix(name, s)) {
+			return d, true
+		}
+	}
+
+	return 0, false
+}
@@ -0,0 +1,92 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	test "github.com/tweekes0/pal-bot/internal/testing"
+)
+
+// Wednesday
+var now = time.Date(2022, 6, 1, 12, 30, 0, 0, time.UTC)
+
+func TestParse(t *testing.T) {
+	friday := &Recurrence{Weekday: time.Friday, Hour: 17}
+	daily := &Recurrence{Daily: true, Hour: 9, Minute: 5}
+
+	testCases := []struct {
+		description string
+		input       string
+		next        time.Time
+		recurrence  *Recurrence
+		err         error
+	}{
+		{"delay", "in 10m", now.Add(10 * time.Minute), nil, nil},
+		{"longer delay", "IN 1h30m", now.Add(90 * time.Minute), nil, nil},
+		{"time later today", "18:00", time.Date(2022, 6, 1, 18, 0, 0, 0, time.UTC), nil, nil},
+		{"time that has passed today", "09:00", time.Date(2022, 6, 2, 9, 0, 0, 0, time.UTC), nil, nil},
+		{"every weekday", "every friday 17:00", time.Date(2022, 6, 3, 17, 0, 0, 0, time.UTC), friday, nil},
+		{"every abbreviated weekday", "every fri 17:00", time.Date(2022, 6, 3, 17, 0, 0, 0, time.UTC), friday, nil},
+		{"every day", "every day 09:05", time.Date(2022, 6, 2, 9, 5, 0, 0, time.UTC), daily, nil},
+		{"negative delay", "in -10m", time.Time{}, nil, ErrInvalidTime},
+		{"delay without unit", "in 10", time.Time{}, nil, ErrInvalidTime},
+		{"invalid time", "25:00", time.Time{}, nil, ErrInvalidTime},
+		{"invalid weekday", "every someday 17:00", time.Time{}, nil, ErrInvalidTime},
+		{"recurrence without time", "every friday", time.Time{}, nil, ErrInvalidTime},
+		{"empty", "", time.Time{}, nil, ErrInvalidTime},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			next, r, err := Parse(tc.input, now)
+			test.AssertError(t, err, tc.err)
+			test.AssertType(t, next, tc.next)
+			test.AssertType(t, r, tc.recurrence)
+		})
+	}
+}
+
+func TestRecurrence(t *testing.T) {
+	t.Run("next run of the same weekday", func(t *testing.T) {
+		t.Parallel()
+
+		r := Recurrence{Weekday: time.Wednesday, Hour: 12}
+		test.AssertType(t, r.Next(now), time.Date(2022, 6, 8, 12, 0, 0, 0, time.UTC))
+
+		r = Recurrence{Weekday: time.Wednesday, Hour: 13}
+		test.AssertType(t, r.Next(now), time.Date(2022, 6, 1, 13, 0, 0, 0, time.UTC))
+	})
+
+	t.Run("next run is after the given time", func(t *testing.T) {
+		t.Parallel()
+
+		r := Recurrence{Daily: true, Hour: 12, Minute: 30}
+		test.AssertType(t, r.Next(now), time.Date(2022, 6, 2, 12, 30, 0, 0, time.UTC))
+	})
+
+	t.Run("format and parse", func(t *testing.T) {
+		t.Parallel()
+
+		for _, r := range []Recurrence{
+			{Weekday: time.Sunday, Hour: 0, Minute: 0},
+			{Weekday: time.Friday, Hour: 17, Minute: 45},
+			{Daily: true, Hour: 9, Minute: 5},
+		} {
+			parsed, err := ParseRecurrence(r.String())
+			test.AssertError(t, err, nil)
+			test.AssertType(t, parsed, r)
+		}
+
+		test.AssertType(t, Recurrence{Weekday: time.Friday, Hour: 17}.String(), "every friday 17:00")
+	})
+
+	t.Run("parse invalid recurrence", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ParseRecurrence("in 10m")
+		test.AssertError(t, err, ErrInvalidRecurrence)
+	})
+}
@@ -9,4 +9,5 @@ var (
 	ErrInvalidFile      = errors.New("file is not valid")
 	ErrInvalidDuration  = errors.New("duration is not valid")
 	ErrInvalidStartTime = errors.New("start time is not valid")
+	ErrCorruptFile      = errors.New("file is corrupt")
 )
@@ -32,13 +32,13 @@ const (
 func HashFile(filename string) (string, error) {
 	f, err := os.Open(filename)
 	if err != nil {
-		return "", nil
+		return "", err
 	}
 	defer f.Close()
 
 	h := sha256.New()
 	if _, err := io.Copy(h, f); err != nil {
-		return "", nil
+		return "", err
 	}
 
 	s := fmt.Sprintf("%x", h.Sum(nil))
@@ -54,7 +54,7 @@ func getFilename(filepath string) string {
 }
 
 // Converts the starttime string(00:00:00 or 00:00 or 00) to time.Duration
-func stringToDuration(t string) (time.Duration, error) {
+func StringToDuration(t string) (time.Duration, error) {
 	if checkShortDuration(t) {
 		i, err := strconv.Atoi(t)
 		if err != nil {
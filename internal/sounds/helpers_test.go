@@ -36,6 +36,7 @@ func TestGetFilename(t *testing.T) {
 	}
 
 	for _, tc := range tt {
+		tc := tc
 		t.Run(tc.description, func(t *testing.T) {
 			t.Parallel()
 			got := getFilename(tc.input)
@@ -47,7 +48,7 @@ func TestGetFilename(t *testing.T) {
 func startToDurationTestFunc(t *testing.T, input, expectedInput string, expectedErr error) {
 	t.Parallel()
 
-	got, err := stringToDuration(input)
+	got, err := StringToDuration(input)
 	expected, _ := time.ParseDuration(expectedInput)
 
 	test.AssertError(t, err, expectedErr)
@@ -57,7 +58,7 @@ func startToDurationTestFunc(t *testing.T, input, expectedInput string, expected
 
 }
 
-func TeststringToDuration(t *testing.T) {
+func TestStringToDuration(t *testing.T) {
 
 	tt := []struct {
 		description string
@@ -90,6 +91,7 @@ func TeststringToDuration(t *testing.T) {
 	}
 
 	for _, tc := range tt {
+		tc := tc
 		t.Run(tc.description, func(t *testing.T) {
 			tc.testFunc(t, tc.input, tc.expected, tc.expectedErr)
 		})
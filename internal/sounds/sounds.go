@@ -11,54 +11,76 @@ import (
 	"github.com/kkdai/youtube/v2"
 	"github.com/tweekes0/pal-bot/config"
 	ffmpeg "github.com/u2takey/ffmpeg-go"
+	"layeh.com/gopus"
 )
 
-// Downloads a youtube video and returns an mp4 file if the download is successful.
-func downloadYoutubeVideo(url string) (*os.File, time.Duration, error) {
+const (
+	MAX_FRAME_SIZE = 1275                  // The largest size in bytes of a single opus frame
+	FRAME_DURATION = 20 * time.Millisecond // The amount of audio in a single DCA frame
+	SAMPLE_RATE    = 48000                 // The sample rate of DCA audio
+	CHANNELS       = 2                     // The number of channels of DCA audio
+	FRAME_SIZE     = 960                   // The number of samples per channel in a single DCA frame
+)
+
+// Struct for the metadata of a youtube video that soundbites are clipped from
+type VideoInfo struct {
+	Title    string
+	Author   string
+	Duration time.Duration
+}
+
+// Downloads a youtube video and returns an mp4 file and the video's metadata if the download is successful.
+func downloadYoutubeVideo(url string) (*os.File, *VideoInfo, error) {
 	client := &youtube.Client{}
 	video, err := client.GetVideo(url)
 	if err != nil {
-		return nil, 0, err
+		return nil, nil, err
 	}
 
 	format := video.Formats.WithAudioChannels()
 	stream, _, err := client.GetStream(video, &format[0])
 	if err != nil {
-		return nil, 0, err
+		return nil, nil, err
 	}
 
 	file, err := os.CreateTemp("", "*.mp4")
 	if err != nil {
-		return nil, 0, err
+		return nil, nil, err
 	}
 	defer file.Close()
 
 	_, err = io.Copy(file, stream)
 	if err != nil {
-		return nil, 0, err
+		return nil, nil, err
+	}
+
+	info := &VideoInfo{
+		Title:    video.Title,
+		Author:   video.Author,
+		Duration: video.Duration,
 	}
 
-	return file, video.Duration, nil
+	return file, info, nil
 }
 
-// Converts an mp4 file to a AAC file.
-func createAACFile(path, url, startTime string, duration int) (*os.File, error) {
-	videoFile, d, err := downloadYoutubeVideo(url)
+// Converts an mp4 file to a AAC file and returns the metadata of the video it was downloaded from.
+func createAACFile(path, url, startTime string, duration int) (*os.File, *VideoInfo, error) {
+	videoFile, video, err := downloadYoutubeVideo(url)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	st, err := stringToDuration(startTime)
+	st, err := StringToDuration(startTime)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	if st.Seconds() > d.Seconds() {
-		return nil, ErrInvalidStartTime
+	if st.Seconds() > video.Duration.Seconds() {
+		return nil, nil, ErrInvalidStartTime
 	}
 
 	if duration > config.CLIP_MAX_DURATION || duration < 1 {
-		return nil, ErrInvalidDuration
+		return nil, nil, ErrInvalidDuration
 	}
 
 	fname := getFilename(videoFile.Name())
@@ -72,26 +94,27 @@ func createAACFile(path, url, startTime string, duration int) (*os.File, error)
 	err = ffmpeg.Input(videoFile.Name()).
 		Output(output, kwargs).OverWriteOutput().Run()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	audio, err := os.Open(output)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	defer audio.Close()
 
 	DeleteFile(videoFile.Name())
 
-	return audio, nil
+	return audio, video, nil
 }
 
 // Converts and AAC file to a DCA file, file that can be streamed to discord VoiceChannel.
-// Returns a the DCA file and an MP3 file that is needed to be sent as an embed to a TextChannel.
-func CreateDCAFile(path, url, startTime string, duration int) (*os.File, *os.File, error) {
-	aac, err := createAACFile(path, url, startTime, duration)
+// Returns a the DCA file and an MP3 file that is needed to be sent as an embed to a TextChannel,
+// along with the metadata of the video it was clipped from.
+func CreateDCAFile(path, url, startTime string, duration int) (*os.File, *os.File, *VideoInfo, error) {
+	aac, video, err := createAACFile(path, url, startTime, duration)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
 	c1 := exec.Command("ffmpeg", "-i", aac.Name(), "-f", "s16le", "-ar", "48000", "-ac", "2", "pipe:1")
@@ -99,13 +122,13 @@ func CreateDCAFile(path, url, startTime string, duration int) (*os.File, *os.Fil
 
 	c2.Stdin, err = c1.StdoutPipe()
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
 	fname := getFilename(aac.Name())
 	f, err := os.Create(fmt.Sprintf("%v/%v.dca", path, fname))
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 	defer f.Close()
 
@@ -113,30 +136,30 @@ func CreateDCAFile(path, url, startTime string, duration int) (*os.File, *os.Fil
 
 	err = c2.Start()
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
 	err = c1.Run()
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
 	err = c2.Wait()
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
 	mp3, err := createMP3File(aac)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
 	err = DeleteFile(aac.Name())
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
-	return f, mp3, nil
+	return f, mp3, video, nil
 }
 
 // Convert an AAC file into a MP3 using FFMPEG
@@ -195,3 +218,131 @@ func LoadSound(filepath string) ([][]byte, error) {
 		b = append(b, inBuf)
 	}
 }
+
+// Reads every frame of a DCA file and returns the number of frames it holds.
+// Returns ErrCorruptFile if a frame header or payload is truncated, a frame has an
+// impossible length or the file holds no frames at all.
+func ValidateDCA(filepath string) (int, error) {
+	file, err := os.Open(filepath)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	var opusLen int16
+	frames := 0
+
+	for {
+		err = binary.Read(file, binary.LittleEndian, &opusLen)
+		if err == io.EOF {
+			if frames == 0 {
+				return 0, ErrCorruptFile
+			}
+
+			return frames, nil
+		}
+
+		if err == io.ErrUnexpectedEOF {
+			return frames, ErrCorruptFile
+		}
+
+		if err != nil {
+			return frames, err
+		}
+
+		if opusLen <= 0 || opusLen > MAX_FRAME_SIZE {
+			return frames, ErrCorruptFile
+		}
+
+		if _, err = io.CopyN(io.Discard, file, int64(opusLen)); err != nil {
+			if err == io.EOF {
+				return frames, ErrCorruptFile
+			}
+
+			return frames, err
+		}
+
+		frames++
+	}
+}
+
+// Gets the playback duration of a DCA file
+func DCADuration(filepath string) (time.Duration, error) {
+	frames, err := ValidateDCA(filepath)
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(frames) * FRAME_DURATION, nil
+}
+
+// Decodes opus frames into interleaved 16-bit PCM samples
+func decodeFrames(frames [][]byte) ([]int16, error) {
+	decoder, err := gopus.NewDecoder(SAMPLE_RATE, CHANNELS)
+	if err != nil {
+		return nil, err
+	}
+
+	pcm := make([]int16, 0, len(frames)*FRAME_SIZE*CHANNELS)
+	for _, frame := range frames {
+		samples, err := decoder.Decode(frame, FRAME_SIZE, false)
+		if err != nil {
+			return nil, ErrCorruptFile
+		}
+
+		pcm = append(pcm, samples...)
+	}
+
+	return pcm, nil
+}
+
+// Converts a DCA file into an MP3 using FFMPEG so a soundbite can be sent to a TextChannel
+func DCAToMP3(filepath string) (_ *os.File, err error) {
+	frames, err := LoadSound(filepath)
+	if err != nil {
+		return nil, err
+	}
+
+	pcm, err := decodeFrames(frames)
+	if err != nil {
+		return nil, err
+	}
+
+	mp3, err := os.CreateTemp("", "*.mp3")
+	if err != nil {
+		return nil, err
+	}
+
+	// the file is only handed to the caller once ffmpeg has written it
+	defer func() {
+		if err != nil {
+			mp3.Close()
+			os.Remove(mp3.Name())
+		}
+	}()
+
+	c := exec.Command("ffmpeg", "-f", "s16le", "-ar", fmt.Sprint(SAMPLE_RATE), "-ac", fmt.Sprint(CHANNELS),
+		"-i", "pipe:0", "-acodec", "libmp3lame", "-y", mp3.Name())
+
+	stdin, err := c.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	if err = c.Start(); err != nil {
+		return nil, err
+	}
+
+	err = binary.Write(stdin, binary.LittleEndian, pcm)
+	stdin.Close()
+	if err != nil {
+		_ = c.Wait()
+		return nil, err
+	}
+
+	if err = c.Wait(); err != nil {
+		return nil, err
+	}
+
+	return mp3, nil
+}
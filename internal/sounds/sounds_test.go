@@ -1,13 +1,17 @@
 package sounds
 
 import (
+	"bytes"
+	"encoding/binary"
 	"io/ioutil"
 	"os"
 	"testing"
+	"time"
 
 	test "github.com/tweekes0/pal-bot/internal/testing"
 
 	"github.com/kkdai/youtube/v2"
+	"layeh.com/gopus"
 )
 
 type fileTestCase struct {
@@ -130,7 +134,7 @@ func TestCreateAACFile(t *testing.T) {
 			dir, _ := ioutil.TempDir("", "*")
 			defer os.RemoveAll(dir)
 
-			f, got := createAACFile(dir, tc.input.url, tc.input.start, tc.input.duration)
+			f, _, got := createAACFile(dir, tc.input.url, tc.input.start, tc.input.duration)
 			if f != nil {
 				defer DeleteFile(f.Name())
 			}
@@ -176,7 +180,7 @@ func TestCreateDCAFile(t *testing.T) {
 			dir, _ := ioutil.TempDir("", "*")
 			defer os.RemoveAll(dir)
 
-			dca, mp3, got := CreateDCAFile(dir, tc.input.url, tc.input.start, tc.input.duration)
+			dca, mp3, _, got := CreateDCAFile(dir, tc.input.url, tc.input.start, tc.input.duration)
 			if dca != nil && mp3 != nil {
 				defer DeleteFile(dca.Name())
 				defer DeleteFile(mp3.Name())
@@ -192,7 +196,7 @@ func TestCreateMP3File(t *testing.T) {
 		dir, _ := ioutil.TempDir("", "*")
 		defer os.RemoveAll(dir)
 
-		aac, err := createAACFile(dir, testURL, "00:00", 10)
+		aac, _, err := createAACFile(dir, testURL, "00:00", 10)
 		defer DeleteFile(aac.Name())
 
 		test.AssertError(t, err, nil)
@@ -220,7 +224,7 @@ func TestLoadSound(t *testing.T) {
 		dir, _ := ioutil.TempDir("", "*")
 		defer os.RemoveAll(dir)
 
-		dca, mp3, err := CreateDCAFile(dir, testURL, "00:00", 10)
+		dca, mp3, _, err := CreateDCAFile(dir, testURL, "00:00", 10)
 		defer DeleteFile(dca.Name())
 		defer DeleteFile(mp3.Name())
 		test.AssertError(t, err, nil)
@@ -229,3 +233,142 @@ func TestLoadSound(t *testing.T) {
 		test.AssertError(t, err, nil)
 	})
 }
+
+// Writes opus frames to a temporary DCA file followed by any trailing bytes
+func writeDCAFile(t *testing.T, frames [][]byte, trailing []byte) string {
+	f, err := ioutil.TempFile("", "*.dca")
+	if err != nil {
+		t.Fatalf("failed to create file: %v", err)
+	}
+	defer f.Close()
+
+	for _, frame := range frames {
+		_ = binary.Write(f, binary.LittleEndian, int16(len(frame)))
+		_, _ = f.Write(frame)
+	}
+	_, _ = f.Write(trailing)
+
+	return f.Name()
+}
+
+func TestValidateDCA(t *testing.T) {
+	frame := bytes.Repeat([]byte{0xfc}, 120)
+
+	tt := []struct {
+		description string
+		frames      [][]byte
+		trailing    []byte
+		expected    int
+		expectedErr error
+	}{
+		{
+			description: "validate well formed DCA file",
+			frames:      [][]byte{frame, frame, frame},
+			expected:    3,
+			expectedErr: nil,
+		},
+		{
+			description: "validate empty DCA file",
+			expected:    0,
+			expectedErr: ErrCorruptFile,
+		},
+		{
+			description: "validate DCA file with truncated header",
+			frames:      [][]byte{frame},
+			trailing:    []byte{0x78},
+			expected:    1,
+			expectedErr: ErrCorruptFile,
+		},
+		{
+			description: "validate DCA file with truncated frame",
+			frames:      [][]byte{frame},
+			trailing:    []byte{0x78, 0x00, 0xfc, 0xfc},
+			expected:    1,
+			expectedErr: ErrCorruptFile,
+		},
+		{
+			description: "validate DCA file with invalid frame length",
+			frames:      [][]byte{frame},
+			trailing:    []byte{0xff, 0xff},
+			expected:    1,
+			expectedErr: ErrCorruptFile,
+		},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			path := writeDCAFile(t, tc.frames, tc.trailing)
+			defer DeleteFile(path)
+
+			got, err := ValidateDCA(path)
+			test.AssertError(t, err, tc.expectedErr)
+			test.AssertType(t, got, tc.expected)
+		})
+	}
+
+	t.Run("validate missing DCA file", func(t *testing.T) {
+		_, err := ValidateDCA("/path/to/missing.dca")
+		if !os.IsNotExist(err) {
+			t.Fatalf("got: %v, expected: %v", err, os.ErrNotExist)
+		}
+	})
+}
+
+func TestDCADuration(t *testing.T) {
+	t.Run("duration of valid DCA file", func(t *testing.T) {
+		frame := bytes.Repeat([]byte{0xfc}, 120)
+		frames := make([][]byte, 50)
+		for i := range frames {
+			frames[i] = frame
+		}
+
+		path := writeDCAFile(t, frames, nil)
+		defer DeleteFile(path)
+
+		got, err := DCADuration(path)
+		test.AssertError(t, err, nil)
+		test.AssertType(t, got, time.Second)
+	})
+
+	t.Run("duration of corrupt DCA file", func(t *testing.T) {
+		path := writeDCAFile(t, nil, []byte{0x78})
+		defer DeleteFile(path)
+
+		got, err := DCADuration(path)
+		test.AssertError(t, err, ErrCorruptFile)
+		test.AssertType(t, got, time.Duration(0))
+	})
+}
+
+// Encodes silence into opus frames
+func encodeTestFrames(t *testing.T, n int) [][]byte {
+	encoder, err := gopus.NewEncoder(SAMPLE_RATE, CHANNELS, gopus.Audio)
+	if err != nil {
+		t.Fatalf("failed to create encoder: %v", err)
+	}
+
+	frames := make([][]byte, n)
+	for i := range frames {
+		frames[i], err = encoder.Encode(make([]int16, FRAME_SIZE*CHANNELS), FRAME_SIZE, MAX_FRAME_SIZE)
+		if err != nil {
+			t.Fatalf("failed to encode frame: %v", err)
+		}
+	}
+
+	return frames
+}
+
+func TestDecodeFrames(t *testing.T) {
+	t.Run("decode opus frames", func(t *testing.T) {
+		pcm, err := decodeFrames(encodeTestFrames(t, 5))
+		test.AssertError(t, err, nil)
+		test.AssertType(t, len(pcm), 5*FRAME_SIZE*CHANNELS)
+	})
+
+	t.Run("decode invalid opus frames", func(t *testing.T) {
+		_, err := decodeFrames([][]byte{{0xff, 0xff, 0xff}})
+		test.AssertError(t, err, ErrCorruptFile)
+	})
+}